@@ -1,6 +1,6 @@
 module github.com/netskopeoss/netskope-api-client-go
 
-go 1.20
+go 1.21
 
 require (
 	github.com/go-playground/validator/v10 v10.14.1
@@ -0,0 +1,56 @@
+package nsgo
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+// callState is shared by the attempts made by the transports for a single call of sendRequest.
+type callState struct {
+	attempts int32
+}
+
+type (
+	callKey    struct{}
+	attemptKey struct{}
+)
+
+// withCall returns a copy of req tracking the attempts made to send it.
+func withCall(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), callKey{}, &callState{}))
+}
+
+// attemptOf returns the number of the attempt req belongs to, starting at 1.
+// For the request of a call, it is the number of the last attempt made.
+func attemptOf(req *http.Request) int {
+	if n, ok := req.Context().Value(attemptKey{}).(int); ok {
+		return n
+	}
+	if call, ok := req.Context().Value(callKey{}).(*callState); ok {
+		if n := int(atomic.LoadInt32(&call.attempts)); n > 0 {
+			return n
+		}
+	}
+	return 1
+}
+
+// attemptTransport numbers the attempts to send a request, for the transports below it.
+// It sits right below the retrying transport, which sends every attempt through it.
+// It also notifies observer of the retries.
+type attemptTransport struct {
+	next     http.RoundTripper
+	observer Observer
+}
+
+func (t *attemptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := 1
+	if call, ok := req.Context().Value(callKey{}).(*callState); ok {
+		n = int(atomic.AddInt32(&call.attempts, 1))
+	}
+	req = req.WithContext(context.WithValue(req.Context(), attemptKey{}, n))
+	if n > 1 && t.observer != nil {
+		t.observer.RequestRetry(req.Context(), requestInfo(req))
+	}
+	return t.next.RoundTrip(req)
+}
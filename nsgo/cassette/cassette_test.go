@@ -0,0 +1,129 @@
+package cassette_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/cassette"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+// flow provisions a publisher and a tunnel, returning the publisher ID and the tunnel.
+func flow(t *testing.T, nsclient *nsgo.Client) (int, *nsgo.IpsecTunnel) {
+	t.Helper()
+	ctx := context.Background()
+
+	pub, err := nsclient.Publishers.Create(ctx, nsgo.PublisherOptions{Name: "pub-1"})
+	if err != nil {
+		t.Fatalf("Publishers.Create: %v", err)
+	}
+	if _, err := nsclient.Publishers.Token(ctx, nsgo.PublisherOptions{Id: strconv.Itoa(pub.ID)}); err != nil {
+		t.Fatalf("Publishers.Token: %v", err)
+	}
+	if _, err := nsclient.Publishers.ListWithFilter(ctx, `publisher_name eq "pub-1"`); err != nil {
+		t.Fatalf("Publishers.ListWithFilter: %v", err)
+	}
+	tunnel, err := nsclient.IPsec.CreateTunnel(ctx, nsgo.NewIpsecTunnel{Site: "branch-1", Psk: "s3cr3t-psk", Pops: []interface{}{"US-SJC1"}})
+	if err != nil {
+		t.Fatalf("IPsec.CreateTunnel: %v", err)
+	}
+	return pub.ID, tunnel
+}
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "flow.json")
+
+	srv := nsgotest.NewServer()
+	nsclient := srv.Client()
+	rec := cassette.NewRecorder(path, nsclient.HttpClient.Transport)
+	nsclient.HttpClient.Transport = rec
+	pubID, tunnel := flow(t, nsclient)
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{nsgotest.DefaultToken, "s3cr3t-psk", "token-"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	replayer, err := cassette.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	replayed, err := nsgo.New("https://replay.invalid", nsgo.WithAPIToken("other-token"), nsgo.WithTransport(replayer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotPubID, gotTunnel := flow(t, replayed)
+	if gotPubID != pubID || gotTunnel.ID != tunnel.ID || gotTunnel.Site != "branch-1" {
+		t.Errorf("replayed publisher %d and tunnel %+v, want %d and %+v", gotPubID, gotTunnel, pubID, tunnel)
+	}
+	if n := replayer.Remaining(); n != 0 {
+		t.Errorf("Remaining() = %d, want 0", n)
+	}
+
+	if _, err := replayed.Publishers.List(context.Background()); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("unrecorded request: err = %v", err)
+	}
+}
+
+func TestReplayMatchesBody(t *testing.T) {
+	c := &cassette.Cassette{Interactions: []cassette.Interaction{
+		{
+			Request:  cassette.Request{Method: "POST", Path: "/api/v2/infrastructure/publishers", Body: cassette.Body(`{"name":"a"}`)},
+			Response: cassette.Response{StatusCode: 200, Body: cassette.Body(`{"status":"success","data":{"id":1,"name":"a"}}`)},
+		},
+		{
+			Request:  cassette.Request{Method: "POST", Path: "/api/v2/infrastructure/publishers", Body: cassette.Body(`{"name":"b"}`)},
+			Response: cassette.Response{StatusCode: 200, Body: cassette.Body(`{"status":"success","data":{"id":2,"name":"b"}}`)},
+		},
+	}}
+	nsclient, _ := nsgo.New("https://replay.invalid", nsgo.WithTransport(cassette.NewReplayer(c)))
+
+	pub, err := nsclient.Publishers.Create(context.Background(), nsgo.PublisherOptions{Name: "b"})
+	if err != nil || pub.ID != 2 {
+		t.Errorf("Create(b) = %+v, %v; want publisher 2", pub, err)
+	}
+	if _, err := nsclient.Publishers.Create(context.Background(), nsgo.PublisherOptions{Name: "b"}); err == nil {
+		t.Error("an interaction was replayed twice")
+	}
+}
+
+func TestBodyRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name, body, encoded string
+	}{
+		{"string", `"accepted"`, `"\"accepted\""`},
+		{"object", `{"status":"success","data":[1,2]}`, `{"status":"success","data":[1,2]}`},
+		{"non-JSON", `upstream connect error`, `"upstream connect error"`},
+		{"null", `null`, `"null"`},
+	} {
+		encoded, err := json.Marshal(cassette.Body(tc.body))
+		if err != nil {
+			t.Fatalf("%s: Marshal: %v", tc.name, err)
+		}
+		if string(encoded) != tc.encoded {
+			t.Errorf("%s: encoded as %s, want %s", tc.name, encoded, tc.encoded)
+		}
+		var decoded cassette.Body
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("%s: Unmarshal: %v", tc.name, err)
+		}
+		if string(decoded) != tc.body {
+			t.Errorf("%s: read back %s, want %s", tc.name, decoded, tc.body)
+		}
+	}
+}
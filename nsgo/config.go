@@ -0,0 +1,460 @@
+package nsgo
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by LoadConfig.
+const (
+	EnvBaseURL    = "NS_BaseURL"
+	EnvAPIToken   = "NS_ApiToken"
+	EnvProfile    = "NS_Profile"
+	EnvConfigFile = "NS_ConfigFile"
+)
+
+// DefaultProfile is the profile used when none is named.
+const DefaultProfile = "default"
+
+// A Profile holds the settings of a Client, as read by LoadConfig. Empty strings and nil pointers
+// are unset, so that a Timeout of 0, which means no timeout, or a RetryBudget of 0, which means no
+// budget, can be set.
+//
+// The token comes from one of APIToken, TokenEnv (the name of an environment variable),
+// TokenFile or TokenCommand (a command line, split on white space, whose output is kept for
+// TokenTTL); see CredentialsProvider. Retries are enabled when any of RetryMax, RetryWaitMin,
+// RetryWaitMax, RetryBudget and VerifyCreates is set, from DefaultRetryPolicy. CAFile adds
+// certificate authorities to the system ones, and CertFile and KeyFile set a client certificate.
+type Profile struct {
+	BaseURL string
+
+	APIToken     string
+	TokenEnv     string
+	TokenFile    string
+	TokenCommand string
+	TokenTTL     time.Duration
+
+	Timeout *time.Duration
+	Proxy   string
+
+	RetryMax      *int
+	RetryWaitMin  *time.Duration
+	RetryWaitMax  *time.Duration
+	RetryBudget   *int
+	VerifyCreates *bool
+
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify *bool
+}
+
+// ConfigSource selects the profile read by LoadConfig.
+type ConfigSource struct {
+	// Profile is the name of the profile. Empty means the NS_Profile environment variable,
+	// or "default".
+	Profile string
+	// File is the configuration file. Empty means the NS_ConfigFile environment variable,
+	// or ~/.netskope/config, which may be missing.
+	File string
+	// Overrides take precedence over the file and the environment.
+	Overrides Profile
+}
+
+// LoadConfig returns a Client for the profile selected by src, with opts applied last.
+//
+// The settings come from, by order of precedence, src.Overrides, the NS_BaseURL and NS_ApiToken
+// environment variables, and the profile in the configuration file. The environment variables
+// are only read when no profile is named, in src or NS_Profile, and as a pair: NS_BaseURL replaces
+// the base URL and the token of the profile with its own and NS_ApiToken, and NS_ApiToken is
+// ignored without it. To combine a profile with a token from the environment, set token_env in
+// the profile. The file is either INI:
+//
+//	[default]
+//	base_url = https://example-tenant.goskope.com
+//	token_env = NS_ApiToken
+//
+//	[profile staging]
+//	base_url = https://staging-tenant.goskope.com
+//	token_command = vault kv get -field=token secret/netskope-staging
+//	retry_max = 4
+//
+// or YAML, with a mapping per profile:
+//
+//	default:
+//	  base_url: https://example-tenant.goskope.com
+//	  token_file: ~/.netskope/token
+//	  timeout: 30s
+//
+// The keys are base_url, api_token, token_env, token_file, token_command, token_ttl, timeout,
+// proxy, retry_max, retry_wait_min, retry_wait_max, retry_budget, verify_creates, ca_file,
+// cert_file, key_file and insecure_skip_verify, after the fields of Profile. Durations are
+// written like "1m30s", or as a number of seconds. Keys set to 0 are applied: timeout = 0 means
+// no timeout.
+func LoadConfig(src ConfigSource, opts ...Option) (*Client, error) {
+	p, err := LoadProfile(src)
+	if err != nil {
+		return nil, err
+	}
+	profileOpts, err := p.options()
+	if err != nil {
+		return nil, err
+	}
+	return New(p.BaseURL, append(profileOpts, opts...)...)
+}
+
+// LoadProfile returns the settings LoadConfig builds a Client from.
+func LoadProfile(src ConfigSource) (Profile, error) {
+	name := src.Profile
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	named := name != ""
+	if !named {
+		name = DefaultProfile
+	}
+
+	path := src.File
+	if path == "" {
+		path = os.Getenv(EnvConfigFile)
+	}
+	optional := path == ""
+	if optional {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			path = filepath.Join(home, ".netskope", "config")
+		}
+	}
+
+	var p Profile
+	if path != "" {
+		data, err := os.ReadFile(expandHome(path))
+		switch {
+		case errors.Is(err, os.ErrNotExist) && optional:
+		case err != nil:
+			return Profile{}, fmt.Errorf("nsgo: reading config: %w", err)
+		default:
+			profiles, err := parseConfig(data)
+			if err != nil {
+				return Profile{}, fmt.Errorf("nsgo: %s: %w", path, err)
+			}
+			values, ok := profiles[name]
+			if !ok && name != DefaultProfile {
+				return Profile{}, fmt.Errorf("nsgo: %s: no profile %q", path, name)
+			}
+			for _, v := range values {
+				if err := p.set(v.key, v.value); err != nil {
+					return Profile{}, fmt.Errorf("nsgo: %s:%d: %w", path, v.line, err)
+				}
+			}
+		}
+	}
+
+	// The environment names a tenant and its token together, so that a token is never sent to
+	// the tenant of another source. It does not apply to a profile named explicitly.
+	if baseURL := os.Getenv(EnvBaseURL); baseURL != "" && !named {
+		p.BaseURL = baseURL
+		p.APIToken, p.TokenEnv, p.TokenFile, p.TokenCommand, p.TokenTTL = os.Getenv(EnvAPIToken), "", "", "", 0
+	}
+	p.merge(src.Overrides)
+	if p.BaseURL == "" {
+		return Profile{}, fmt.Errorf("nsgo: no base URL for profile %q", name)
+	}
+	return p, nil
+}
+
+// set sets the field of p named key in configuration files.
+func (p *Profile) set(key, value string) error {
+	var err error
+	switch key {
+	case "base_url":
+		p.BaseURL = value
+	case "api_token":
+		p.APIToken = value
+	case "token_env":
+		p.TokenEnv = value
+	case "token_file":
+		p.TokenFile = value
+	case "token_command":
+		p.TokenCommand = value
+	case "token_ttl":
+		p.TokenTTL, err = parseConfigDuration(value)
+	case "timeout":
+		p.Timeout, err = parseConfigDurationPtr(value)
+	case "proxy":
+		p.Proxy = value
+	case "retry_max":
+		p.RetryMax, err = parseConfigInt(value)
+	case "retry_wait_min":
+		p.RetryWaitMin, err = parseConfigDurationPtr(value)
+	case "retry_wait_max":
+		p.RetryWaitMax, err = parseConfigDurationPtr(value)
+	case "retry_budget":
+		p.RetryBudget, err = parseConfigInt(value)
+	case "verify_creates":
+		p.VerifyCreates, err = parseConfigBool(value)
+	case "ca_file":
+		p.CAFile = value
+	case "cert_file":
+		p.CertFile = value
+	case "key_file":
+		p.KeyFile = value
+	case "insecure_skip_verify":
+		p.InsecureSkipVerify, err = parseConfigBool(value)
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// merge sets the fields of p that are set in o. The token source is replaced as a whole.
+func (p *Profile) merge(o Profile) {
+	setString := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	setDuration := func(dst **time.Duration, v *time.Duration) {
+		if v != nil {
+			*dst = v
+		}
+	}
+	setInt := func(dst **int, v *int) {
+		if v != nil {
+			*dst = v
+		}
+	}
+	setBool := func(dst **bool, v *bool) {
+		if v != nil {
+			*dst = v
+		}
+	}
+
+	setString(&p.BaseURL, o.BaseURL)
+	if o.APIToken != "" || o.TokenEnv != "" || o.TokenFile != "" || o.TokenCommand != "" {
+		p.APIToken, p.TokenEnv, p.TokenFile, p.TokenCommand, p.TokenTTL = o.APIToken, o.TokenEnv, o.TokenFile, o.TokenCommand, o.TokenTTL
+	}
+	setDuration(&p.Timeout, o.Timeout)
+	setString(&p.Proxy, o.Proxy)
+	setInt(&p.RetryMax, o.RetryMax)
+	setDuration(&p.RetryWaitMin, o.RetryWaitMin)
+	setDuration(&p.RetryWaitMax, o.RetryWaitMax)
+	setInt(&p.RetryBudget, o.RetryBudget)
+	setBool(&p.VerifyCreates, o.VerifyCreates)
+	setString(&p.CAFile, o.CAFile)
+	setString(&p.CertFile, o.CertFile)
+	setString(&p.KeyFile, o.KeyFile)
+	setBool(&p.InsecureSkipVerify, o.InsecureSkipVerify)
+}
+
+// options returns the Options applying p.
+func (p Profile) options() ([]Option, error) {
+	var opts []Option
+	switch {
+	case p.APIToken != "":
+		opts = append(opts, WithAPIToken(p.APIToken))
+	case p.TokenEnv != "":
+		opts = append(opts, WithCredentials(EnvCredentials(p.TokenEnv)))
+	case p.TokenFile != "":
+		opts = append(opts, WithCredentials(NewFileCredentials(expandHome(p.TokenFile))))
+	case strings.TrimSpace(p.TokenCommand) != "":
+		args := strings.Fields(p.TokenCommand)
+		opts = append(opts, WithCredentials(NewCommandCredentials(p.TokenTTL, args[0], args[1:]...)))
+	default:
+		return nil, errors.New("nsgo: no API token in profile")
+	}
+
+	if p.Timeout != nil {
+		opts = append(opts, WithTimeout(*p.Timeout))
+	}
+	if p.RetryMax != nil || p.RetryWaitMin != nil || p.RetryWaitMax != nil || p.RetryBudget != nil || p.VerifyCreates != nil {
+		policy := DefaultRetryPolicy()
+		if p.RetryMax != nil {
+			policy.MaxRetries = *p.RetryMax
+		}
+		if p.RetryWaitMin != nil {
+			policy.MinWait = *p.RetryWaitMin
+		}
+		if p.RetryWaitMax != nil {
+			policy.MaxWait = *p.RetryWaitMax
+		}
+		if p.RetryBudget != nil {
+			policy.Budget = *p.RetryBudget
+		}
+		if p.VerifyCreates != nil {
+			policy.VerifyCreates = *p.VerifyCreates
+		}
+		opts = append(opts, WithRetryPolicy(policy))
+	}
+
+	tlsConfig, err := p.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		opts = append(opts, WithTransport(transport))
+	}
+	//WithProxy comes after WithTransport, which it applies to.
+	if p.Proxy != "" {
+		opts = append(opts, WithProxy(p.Proxy))
+	}
+	return opts, nil
+}
+
+// tlsConfig returns the TLS configuration set by p, or nil if p sets none.
+func (p Profile) tlsConfig() (*tls.Config, error) {
+	insecure := p.InsecureSkipVerify != nil && *p.InsecureSkipVerify
+	if p.CAFile == "" && p.CertFile == "" && !insecure {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: insecure}
+	if p.CAFile != "" {
+		pem, err := os.ReadFile(expandHome(p.CAFile))
+		if err != nil {
+			return nil, fmt.Errorf("nsgo: reading CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("nsgo: no certificate in %s", p.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if p.CertFile != "" {
+		keyFile := p.KeyFile
+		if keyFile == "" {
+			keyFile = p.CertFile
+		}
+		cert, err := tls.LoadX509KeyPair(expandHome(p.CertFile), expandHome(keyFile))
+		if err != nil {
+			return nil, fmt.Errorf("nsgo: loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// configValue is a key of a profile in a configuration file.
+type configValue struct {
+	key, value string
+	line       int
+}
+
+// parseConfig returns the keys of every profile of an INI or YAML configuration file.
+// INI sections may be named "[name]" or "[profile name]".
+func parseConfig(data []byte) (map[string][]configValue, error) {
+	profiles := map[string][]configValue{}
+	ini := false
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == '#' || line[0] == ';' || line == "---" {
+			continue
+		}
+		if section == "" && line[0] == '[' {
+			ini = true
+		}
+
+		switch {
+		case ini && line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid section %s", n, line)
+			}
+			section = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line[1:len(line)-1]), "profile "))
+			profiles[section] = profiles[section]
+		case !ini && raw[0] != ' ' && raw[0] != '\t':
+			name, rest, ok := strings.Cut(line, ":")
+			if !ok || strings.TrimSpace(rest) != "" {
+				return nil, fmt.Errorf("line %d: want a profile name followed by a colon", n)
+			}
+			section = unquote(strings.TrimSpace(name))
+			profiles[section] = profiles[section]
+		default:
+			sep := ":"
+			if ini {
+				sep = "="
+			}
+			key, value, ok := strings.Cut(line, sep)
+			if !ok {
+				return nil, fmt.Errorf("line %d: want key %s value", n, sep)
+			}
+			if section == "" {
+				return nil, fmt.Errorf("line %d: key outside of a profile", n)
+			}
+			value = strings.TrimSpace(value)
+			if i := strings.Index(value, " #"); i >= 0 && !ini {
+				value = strings.TrimSpace(value[:i])
+			}
+			profiles[section] = append(profiles[section], configValue{key: strings.TrimSpace(key), value: unquote(value), line: n})
+		}
+	}
+	return profiles, scanner.Err()
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func parseConfigDuration(s string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(s); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func parseConfigDurationPtr(s string) (*time.Duration, error) {
+	d, err := parseConfigDuration(s)
+	if err != nil {
+		return nil, err
+	}
+	return Duration(d), nil
+}
+
+func parseConfigInt(s string) (*int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return Int(v), nil
+}
+
+func parseConfigBool(s string) (*bool, error) {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, err
+	}
+	return Bool(v), nil
+}
+
+// expandHome replaces a leading "~/" in path by the home directory of the user.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
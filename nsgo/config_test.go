@@ -0,0 +1,196 @@
+package nsgo_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+// isolateConfig keeps the tests of LoadConfig from reading the environment and home of the user.
+func isolateConfig(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	for _, name := range []string{nsgo.EnvBaseURL, nsgo.EnvAPIToken, nsgo.EnvProfile, nsgo.EnvConfigFile} {
+		t.Setenv(name, "")
+	}
+	return dir
+}
+
+func writeConfig(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigINI(t *testing.T) {
+	dir := isolateConfig(t)
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	path := writeConfig(t, dir, `
+# Tenants
+[default]
+base_url = https://example-tenant.goskope.com
+token_env = NS_ApiToken
+
+[profile staging]
+base_url = `+srv.URL+`
+api_token = "`+nsgotest.DefaultToken+`"
+retry_max = 2
+retry_wait_min = 1ms
+retry_wait_max = 5ms
+timeout = 10
+`)
+	t.Setenv(nsgo.EnvProfile, "staging")
+
+	nsclient, err := nsgo.LoadConfig(nsgo.ConfigSource{File: path})
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	srv.Fail(nsgotest.Failure{Status: http.StatusServiceUnavailable})
+	if _, err := nsclient.Publishers.List(context.Background()); err != nil {
+		t.Fatalf("Publishers.List: %v", err)
+	}
+	if nsclient.HttpClient.Timeout != 10*time.Second {
+		t.Errorf("Timeout = %v, want 10s", nsclient.HttpClient.Timeout)
+	}
+}
+
+// Keys set to zero are applied, and the retry keys apply without retry_max.
+func TestLoadConfigExplicitZero(t *testing.T) {
+	dir := isolateConfig(t)
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	path := writeConfig(t, dir, `
+[default]
+base_url = `+srv.URL+`
+api_token = `+nsgotest.DefaultToken+`
+timeout = 0
+retry_wait_min = 1ms
+retry_wait_max = 5ms
+`)
+
+	p, err := nsgo.LoadProfile(nsgo.ConfigSource{File: path})
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if p.Timeout == nil || *p.Timeout != 0 || p.RetryMax != nil {
+		t.Errorf("LoadProfile = %+v, want a zero Timeout and no RetryMax", p)
+	}
+	nsclient, err := nsgo.LoadConfig(nsgo.ConfigSource{File: path})
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if nsclient.HttpClient.Timeout != 0 {
+		t.Errorf("Timeout = %v, want none", nsclient.HttpClient.Timeout)
+	}
+	srv.Fail(nsgotest.Failure{Status: http.StatusServiceUnavailable, Times: 1})
+	if _, err := nsclient.Publishers.List(context.Background()); err != nil {
+		t.Errorf("Publishers.List with retry_wait_min and retry_wait_max only: %v", err)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	dir := isolateConfig(t)
+	if err := os.MkdirAll(filepath.Join(dir, ".netskope"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, filepath.Join(dir, ".netskope"), `
+default:
+  base_url: https://file-tenant.goskope.com  # replaced by NS_BaseURL
+  token_file: ~/token
+  timeout: 1m
+  proxy: 'http://proxy.example:3128'
+staging:
+  base_url: https://staging-tenant.goskope.com
+  api_token: staging-token
+`)
+
+	// NS_ApiToken alone is not sent to the tenant of the profile.
+	t.Setenv(nsgo.EnvAPIToken, "env-token")
+	p, err := nsgo.LoadProfile(nsgo.ConfigSource{Overrides: nsgo.Profile{Timeout: nsgo.Duration(5 * time.Second)}})
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	want := nsgo.Profile{
+		BaseURL:   "https://file-tenant.goskope.com",
+		TokenFile: "~/token",
+		Timeout:   nsgo.Duration(5 * time.Second),
+		Proxy:     "http://proxy.example:3128",
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("LoadProfile = %+v, want %+v", p, want)
+	}
+
+	// NS_BaseURL and NS_ApiToken replace the tenant of the default profile together.
+	t.Setenv(nsgo.EnvBaseURL, "https://env-tenant.goskope.com")
+	p, err = nsgo.LoadProfile(nsgo.ConfigSource{})
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if p.BaseURL != "https://env-tenant.goskope.com" || p.APIToken != "env-token" || p.TokenFile != "" {
+		t.Errorf("LoadProfile with NS_BaseURL and NS_ApiToken = %+v", p)
+	}
+	t.Setenv(nsgo.EnvAPIToken, "")
+	p, err = nsgo.LoadProfile(nsgo.ConfigSource{})
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if p.BaseURL != "https://env-tenant.goskope.com" || p.APIToken != "" || p.TokenFile != "" {
+		t.Errorf("NS_BaseURL kept the token of the profile: %+v", p)
+	}
+
+	// The environment does not apply to a profile named explicitly.
+	t.Setenv(nsgo.EnvAPIToken, "env-token")
+	for _, src := range []nsgo.ConfigSource{{Profile: "staging"}, {}} {
+		if src.Profile == "" {
+			t.Setenv(nsgo.EnvProfile, "staging")
+		}
+		p, err = nsgo.LoadProfile(src)
+		if err != nil {
+			t.Fatalf("LoadProfile(%+v): %v", src, err)
+		}
+		if p.BaseURL != "https://staging-tenant.goskope.com" || p.APIToken != "staging-token" {
+			t.Errorf("LoadProfile(%+v) with NS_BaseURL and NS_ApiToken = %+v, want the staging profile", src, p)
+		}
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	dir := isolateConfig(t)
+	path := writeConfig(t, dir, "[default]\nbase_url = https://example-tenant.goskope.com\ntoken = x\n")
+
+	for _, tt := range []struct {
+		src  nsgo.ConfigSource
+		want string
+	}{
+		{nsgo.ConfigSource{File: path}, "config:3: unknown key \"token\""},
+		{nsgo.ConfigSource{File: path, Profile: "prod"}, "no profile \"prod\""},
+		{nsgo.ConfigSource{File: filepath.Join(dir, "missing")}, "reading config"},
+		{nsgo.ConfigSource{}, "no base URL"},
+	} {
+		_, err := nsgo.LoadConfig(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("LoadConfig(%+v): err = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+
+	// Without a file, the environment is enough.
+	t.Setenv(nsgo.EnvBaseURL, "https://example-tenant.goskope.com")
+	if _, err := nsgo.LoadConfig(nsgo.ConfigSource{}); err == nil || !strings.Contains(err.Error(), "no API token") {
+		t.Errorf("LoadConfig without a token: err = %v", err)
+	}
+	t.Setenv(nsgo.EnvAPIToken, "env-token")
+	if _, err := nsgo.LoadConfig(nsgo.ConfigSource{}); err != nil {
+		t.Errorf("LoadConfig from the environment: %v", err)
+	}
+}
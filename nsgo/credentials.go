@@ -0,0 +1,193 @@
+package nsgo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A CredentialsProvider supplies the API token of a Client. The client asks for a token before
+// every request, possibly from several goroutines at once, so that rotated tokens are picked up
+// without rebuilding it.
+//
+// When the tenant rejects a request with a 401 or a 403, the client calls Refresh and, if it
+// gets another token, sends the request once more with it.
+type CredentialsProvider interface {
+	// Token returns the token to send with a request.
+	Token(ctx context.Context) (string, error)
+	// Refresh returns a token to replace rejected, bypassing any cache. Providers that cannot
+	// get another token return rejected.
+	Refresh(ctx context.Context, rejected string) (string, error)
+}
+
+// WithCredentials gets the API token of every request from p.
+func WithCredentials(p CredentialsProvider) Option {
+	return func(o *clientOptions) error {
+		if p == nil {
+			return errors.New("nsgo: nil credentials provider")
+		}
+		o.credentials = p
+		return nil
+	}
+}
+
+// StaticCredentials is a fixed token, as set by WithAPIToken.
+type StaticCredentials string
+
+// Token implements CredentialsProvider.
+func (s StaticCredentials) Token(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// Refresh implements CredentialsProvider. A static token cannot be refreshed.
+func (s StaticCredentials) Refresh(context.Context, string) (string, error) {
+	return string(s), nil
+}
+
+// EnvCredentials is the name of an environment variable holding the token, i.e. "NS_ApiToken".
+// The variable is read for every request.
+type EnvCredentials string
+
+// Token implements CredentialsProvider.
+func (e EnvCredentials) Token(context.Context) (string, error) {
+	token := os.Getenv(string(e))
+	if token == "" {
+		return "", fmt.Errorf("nsgo: environment variable %s is not set", string(e))
+	}
+	return token, nil
+}
+
+// Refresh implements CredentialsProvider.
+func (e EnvCredentials) Refresh(ctx context.Context, _ string) (string, error) {
+	return e.Token(ctx)
+}
+
+// FileCredentials reads the token from a file, such as a mounted secret, and reads it again
+// whenever the file changes. Leading and trailing white space is ignored.
+type FileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentials returns a FileCredentials reading the file at path.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// Token implements CredentialsProvider.
+func (f *FileCredentials) Token(context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("nsgo: reading token: %w", err)
+	}
+	if f.token != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.token, nil
+	}
+	return f.read()
+}
+
+// Refresh implements CredentialsProvider.
+func (f *FileCredentials) Refresh(_ context.Context, rejected string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.token != "" && f.token != rejected {
+		//Another request already picked up the new token.
+		return f.token, nil
+	}
+	return f.read()
+}
+
+// read reads the token from the file. Callers hold f.mu.
+func (f *FileCredentials) read() (string, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return "", fmt.Errorf("nsgo: reading token: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("nsgo: reading token: %w", err)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(file); err != nil {
+		return "", fmt.Errorf("nsgo: reading token: %w", err)
+	}
+	token := strings.TrimSpace(buf.String())
+	if token == "" {
+		return "", fmt.Errorf("nsgo: no token in %s", f.path)
+	}
+	f.token, f.modTime, f.size = token, info.ModTime(), info.Size()
+	return token, nil
+}
+
+// CommandCredentials gets the token from the output of a command, such as a secrets manager CLI.
+// Leading and trailing white space is ignored. The token is kept for the TTL given to
+// NewCommandCredentials, and until the tenant rejects it.
+type CommandCredentials struct {
+	name string
+	args []string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	token   string
+	fetched time.Time
+}
+
+// NewCommandCredentials returns a CommandCredentials running name with args, and running it again
+// once the token is older than ttl. A zero ttl keeps the token until the tenant rejects it.
+//
+//	creds := nsgo.NewCommandCredentials(time.Hour, "vault", "kv", "get", "-field=token", "secret/netskope")
+func NewCommandCredentials(ttl time.Duration, name string, args ...string) *CommandCredentials {
+	return &CommandCredentials{name: name, args: args, ttl: ttl}
+}
+
+// Token implements CredentialsProvider.
+func (c *CommandCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && (c.ttl == 0 || time.Since(c.fetched) < c.ttl) {
+		return c.token, nil
+	}
+	return c.run(ctx)
+}
+
+// Refresh implements CredentialsProvider.
+func (c *CommandCredentials) Refresh(ctx context.Context, rejected string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.token != rejected {
+		//Another request already ran the command again.
+		return c.token, nil
+	}
+	return c.run(ctx)
+}
+
+// run runs the command. Callers hold c.mu.
+func (c *CommandCredentials) run(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, c.name, c.args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("nsgo: running %s: %w", c.name, err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("nsgo: %s printed no token", c.name)
+	}
+	c.token, c.fetched = token, time.Now()
+	return token, nil
+}
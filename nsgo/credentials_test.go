@@ -0,0 +1,109 @@
+package nsgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestFileCredentials(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte(nsgotest.DefaultToken+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	nsclient := srv.Client(nsgo.WithCredentials(nsgo.NewFileCredentials(path)))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := nsclient.Publishers.List(ctx); err != nil {
+				t.Errorf("Publishers.List: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	srv.SetToken("rotated-token")
+	if err := os.WriteFile(path, []byte("rotated-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nsclient.Publishers.List(ctx); err != nil {
+		t.Fatalf("Publishers.List after rotation: %v", err)
+	}
+}
+
+func TestCommandCredentialsRefresh(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not available")
+	}
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte(nsgotest.DefaultToken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	nsclient := srv.Client(nsgo.WithCredentials(nsgo.NewCommandCredentials(0, "cat", path)))
+	ctx := context.Background()
+
+	if _, err := nsclient.PrivateApps.List(ctx); err != nil {
+		t.Fatalf("PrivateApps.List: %v", err)
+	}
+
+	// The cached token is rejected once rotated, and the command runs again.
+	srv.SetToken("rotated-token")
+	if err := os.WriteFile(path, []byte("rotated-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nsclient.PrivateApps.Create(ctx, nsgo.PrivateApp{AppName: "app-1", Host: "10.0.0.1"}); err != nil {
+		t.Fatalf("PrivateApps.Create after rotation: %v", err)
+	}
+	requests := srv.Requests()
+	if len(requests) != 3 || requests[1].Method != http.MethodPost || requests[2].Header.Get("Netskope-Api-Token") != "rotated-token" {
+		t.Errorf("requests = %+v, want a rejected create sent again with the rotated token", requests)
+	}
+	if string(requests[2].Body) != string(requests[1].Body) {
+		t.Errorf("replayed body = %s, want %s", requests[2].Body, requests[1].Body)
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client(nsgo.WithCredentials(nsgo.EnvCredentials("NSGO_TEST_TOKEN")))
+
+	t.Setenv("NSGO_TEST_TOKEN", "")
+	if _, err := nsclient.IPsec.ListPops(context.Background()); err == nil {
+		t.Error("IPsec.ListPops succeeded without a token")
+	}
+	t.Setenv("NSGO_TEST_TOKEN", nsgotest.DefaultToken)
+	if _, err := nsclient.IPsec.ListPops(context.Background()); err != nil {
+		t.Errorf("IPsec.ListPops: %v", err)
+	}
+}
+
+func TestStaticCredentialsRejected(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client(nsgo.WithCredentials(nsgo.StaticCredentials("wrong-token")))
+
+	_, err := nsclient.Publishers.List(context.Background())
+	if !errors.Is(err, nsgo.ErrUnauthorized) {
+		t.Errorf("Publishers.List: err = %v, want ErrUnauthorized", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("server received %d requests, want 1", n)
+	}
+}
@@ -10,18 +10,22 @@
 //	package main
 //
 //	import (
+//		"context"
 //		"fmt"
-//		"os"
 //
 //		"github.com/netskopeoss/netskope-api-client-go/netskope"
 //	)
 //
 //	func main() {
-//		//Init a client instance
-//		nsclient := netskope.NewClient(os.Getenv("NS_BaseURL"), os.Getenv("NS_ApiToken"))
+//		//Init a client instance from ~/.netskope/config, or the NS_BaseURL and NS_ApiToken environment variables
+//		nsclient, err := netskope.LoadConfig(netskope.ConfigSource{})
+//		if err != nil {
+//			fmt.Println(err)
+//			return
+//		}
 //
 //		//Get Publishers
-//		pubs, err := nsclient.GetPublishers()
+//		pubs, err := nsclient.Publishers.List(context.Background())
 //		if err != nil {
 //			fmt.Println(err)
 //			return
@@ -0,0 +1,150 @@
+package nsgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// envelope is the body of an API response. Most endpoints report a "success"/"error" string
+// status and carry their payload in data; the IPSec endpoints report an HTTP-like integer
+// status and carry their payload in result.
+type envelope struct {
+	Status  json.RawMessage `json:"status"`
+	Data    json.RawMessage `json:"data"`
+	Result  json.RawMessage `json:"result"`
+	Total   int             `json:"total"`
+	Message json.RawMessage `json:"message"`
+}
+
+// payload checks the status reported by e and returns the payload it carries.
+func (e *envelope) payload(req *http.Request) (json.RawMessage, error) {
+	var text string
+	if err := json.Unmarshal(e.Status, &text); err == nil {
+		if text != "success" {
+			return nil, newEnvelopeError(req, text, e.message())
+		}
+		return firstPresent(e.Data, e.Result), nil
+	}
+
+	var code int
+	if err := json.Unmarshal(e.Status, &code); err == nil {
+		if code < http.StatusOK || code >= http.StatusMultipleChoices {
+			return nil, newIpsecError(req, code, e.message())
+		}
+		return firstPresent(e.Result, e.Data), nil
+	}
+
+	return nil, newEnvelopeError(req, string(e.Status), e.message())
+}
+
+// knownStatus reports whether the status of e is one the API is documented to send.
+func (e *envelope) knownStatus() bool {
+	var text string
+	if err := json.Unmarshal(e.Status, &text); err == nil {
+		return text == "success" || text == "error"
+	}
+	var code int
+	return json.Unmarshal(e.Status, &code) == nil
+}
+
+// message returns the message of e, which the API sends either as a string or as a JSON value.
+func (e *envelope) message() string {
+	var text string
+	if err := json.Unmarshal(e.Message, &text); err == nil {
+		return text
+	}
+	if isNull(e.Message) {
+		return ""
+	}
+	return string(e.Message)
+}
+
+func firstPresent(values ...json.RawMessage) json.RawMessage {
+	for _, v := range values {
+		if !isNull(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+func isNull(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
+
+// newRequest builds a request for path, relative to the tenant URL, with body encoded as JSON.
+func (c *Client) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("nsgo: encoding request body: %w", err)
+		}
+		reader = bytes.NewReader(jsonBody)
+	}
+	return http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+}
+
+// do sends a request and returns the payload of the response envelope along with the
+// total number of items the API reports for list endpoints.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) (json.RawMessage, int, error) {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var raw json.RawMessage
+	if err := c.sendRequest(req, &raw); err != nil {
+		return nil, 0, err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, 0, fmt.Errorf("nsgo: decoding %s %s response: %w", method, req.URL.Path, err)
+	}
+	// Bodies without a status are returned as is.
+	if isNull(env.Status) {
+		return raw, 0, nil
+	}
+	if !env.knownStatus() {
+		c.logger.WarnContext(ctx, "nsgo: unknown response status",
+			"method", method, "path", req.URL.Path, "status", string(env.Status))
+	}
+	payload, err := env.payload(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return payload, env.Total, nil
+}
+
+// doJSON sends a request and decodes the payload of the response envelope into a T.
+// An empty payload yields the zero T.
+func doJSON[T any](ctx context.Context, c *Client, method, path string, body interface{}) (T, error) {
+	var out T
+	payload, _, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return out, err
+	}
+	if err := decodePayload(payload, &out); err != nil {
+		return out, fmt.Errorf("nsgo: decoding %s %s response: %w", method, stripQuery(path), err)
+	}
+	return out, nil
+}
+
+func decodePayload(payload json.RawMessage, v interface{}) error {
+	if isNull(payload) {
+		return nil
+	}
+	return json.Unmarshal(payload, v)
+}
+
+func stripQuery(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	return path
+}
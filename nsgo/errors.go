@@ -0,0 +1,183 @@
+package nsgo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by APIError through errors.Is.
+//
+//	_, err := nsclient.GetPublisherId(nsgo.PublisherOptions{Id: "987"})
+//	if errors.Is(err, nsgo.ErrNotFound) {
+//		// the publisher is gone
+//	}
+var (
+	ErrBadRequest   = errors.New("nsgo: bad request")
+	ErrUnauthorized = errors.New("nsgo: unauthorized")
+	ErrForbidden    = errors.New("nsgo: forbidden")
+	ErrNotFound     = errors.New("nsgo: not found")
+	ErrConflict     = errors.New("nsgo: conflict")
+	ErrRateLimited  = errors.New("nsgo: rate limited")
+	ErrServer       = errors.New("nsgo: server error")
+)
+
+// APIError is returned when the Netskope tenant answers a request with an error,
+// either through the HTTP status code or through the status field of the response body.
+type APIError struct {
+	// StatusCode is the HTTP status code, or the status reported in the response body
+	// when the tenant signalled the failure there instead.
+	StatusCode int
+	Method     string
+	Path       string
+	// Message is the error message sent by the API, if any.
+	Message string
+	// Body is the raw response body.
+	Body []byte
+	// RequestID and TraceID echo the correlation headers sent by the tenant, if any.
+	RequestID string
+	TraceID   string
+	RateLimit RateLimitInfo
+}
+
+// RateLimitInfo holds the rate limit headers sent with a response.
+// Limit and Remaining are -1 when the corresponding header was absent.
+type RateLimitInfo struct {
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "nsgo: %s %s: ", e.Method, e.Path)
+	if text := http.StatusText(e.StatusCode); text != "" {
+		fmt.Fprintf(&b, "%d %s", e.StatusCode, text)
+	} else {
+		fmt.Fprintf(&b, "status %d", e.StatusCode)
+	}
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+	if e.RequestID != "" {
+		b.WriteString(" (request id " + e.RequestID + ")")
+	}
+	return b.String()
+}
+
+// Is reports whether target is the sentinel error matching the status code of e.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a failed HTTP exchange.
+func newAPIError(req *http.Request, res *http.Response, body []byte, message string) *APIError {
+	return &APIError{
+		StatusCode: res.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Message:    message,
+		Body:       body,
+		RequestID:  firstHeader(res.Header, "X-Request-Id", "Netskope-Request-Id", "X-Netskope-Request-Id"),
+		TraceID:    firstHeader(res.Header, "X-Trace-Id", "Netskope-Trace-Id", "X-B3-Traceid"),
+		RateLimit:  parseRateLimit(res.Header),
+	}
+}
+
+// newEnvelopeError builds an APIError for a successful HTTP exchange whose body reports a failure
+// through the "success"/"error" status field.
+func newEnvelopeError(req *http.Request, status, message string) *APIError {
+	if status != "error" {
+		if message != "" {
+			message += "; "
+		}
+		message += "unknown status: " + status
+	}
+	return &APIError{
+		StatusCode: http.StatusOK,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Message:    message,
+		RateLimit:  RateLimitInfo{Limit: -1, Remaining: -1},
+	}
+}
+
+// newIpsecError builds an APIError for an IPSec response whose integer status field reports a failure.
+func newIpsecError(req *http.Request, status int, message string) *APIError {
+	return &APIError{
+		StatusCode: status,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Message:    message,
+		RateLimit:  RateLimitInfo{Limit: -1, Remaining: -1},
+	}
+}
+
+func firstHeader(h http.Header, keys ...string) string {
+	for _, k := range keys {
+		if v := h.Get(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseRateLimit reads the RateLimit-* and Retry-After headers of a response.
+func parseRateLimit(h http.Header) RateLimitInfo {
+	info := RateLimitInfo{Limit: -1, Remaining: -1}
+	if v, err := strconv.Atoi(firstHeader(h, "RateLimit-Limit", "X-RateLimit-Limit")); err == nil {
+		info.Limit = v
+	}
+	if v, err := strconv.Atoi(firstHeader(h, "RateLimit-Remaining", "X-RateLimit-Remaining")); err == nil {
+		info.Remaining = v
+	}
+	info.Reset = parseDelay(firstHeader(h, "RateLimit-Reset", "X-RateLimit-Reset"))
+	info.RetryAfter = parseDelay(h.Get("Retry-After"))
+	return info
+}
+
+// parseDelay interprets a header value as a number of seconds, a unix timestamp or an HTTP date
+// and returns how long from now it points to.
+func parseDelay(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		// Values this large are epoch timestamps rather than relative delays.
+		if secs > 1_000_000_000 {
+			return nonNegative(time.Until(time.Unix(secs, 0)))
+		}
+		return nonNegative(time.Duration(secs) * time.Second)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return nonNegative(time.Until(t))
+	}
+	return 0
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,147 @@
+package nsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The Flex types decode values the API does not always send with the same JSON type.
+// They are defined on the matching Go types, so they can be used as such.
+
+// FlexBool is a bool that also decodes from strings ("true", "false", "yes", "no", "1", "0", "") and numbers.
+type FlexBool bool
+
+// FlexInt is an int that also decodes from numeric strings. An empty string decodes to 0.
+type FlexInt int
+
+// FlexString is a string that also decodes from numbers and booleans.
+type FlexString string
+
+func (b *FlexBool) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if isNull(data) {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = FlexBool(v)
+	case float64:
+		*b = v != 0
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "1":
+			*b = true
+		case "false", "no", "0", "":
+			*b = false
+		default:
+			return fmt.Errorf("nsgo: cannot decode %q as a bool", v)
+		}
+	default:
+		return fmt.Errorf("nsgo: cannot decode %s as a bool", data)
+	}
+	return nil
+}
+
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if isNull(data) {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			*i = 0
+			return nil
+		}
+		data = []byte(s)
+	}
+	n, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("nsgo: cannot decode %s as an int", data)
+	}
+	*i = FlexInt(n)
+	return nil
+}
+
+func (s *FlexString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if isNull(data) {
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = FlexString(str)
+		return nil
+	}
+	switch {
+	case bytes.Equal(data, []byte("true")), bytes.Equal(data, []byte("false")):
+		*s = FlexString(data)
+	case len(data) > 0 && (data[0] == '-' || (data[0] >= '0' && data[0] <= '9')):
+		*s = FlexString(data)
+	default:
+		return fmt.Errorf("nsgo: cannot decode %s as a string", data)
+	}
+	return nil
+}
+
+// decodeObject decodes data into v, treating null, "", [] and {} as an absent object.
+func decodeObject(data []byte, v interface{}) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case isNull(data), bytes.Equal(data, []byte(`""`)), bytes.Equal(data, []byte("[]")):
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// timestampLayouts are the date formats the API uses, tried in order.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseTimestamp decodes a date string, or a unix timestamp in seconds or milliseconds.
+// null, "" and 0 decode to the zero time.
+func parseTimestamp(data json.RawMessage) (time.Time, error) {
+	data = bytes.TrimSpace(data)
+	if isNull(data) {
+		return time.Time{}, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return time.Time{}, nil
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		// Values this large are in milliseconds.
+		if n > 1_000_000_000_000 {
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("nsgo: cannot decode %s as a timestamp", data)
+}
@@ -0,0 +1,422 @@
+package nsgo
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/internal/redact"
+)
+
+// A HARTracer collects the requests sent by a Client, and their responses, as a HAR 1.2 log
+// (http://www.softwareishard.com/blog/har-12-spec/) that can be opened by browsers and attached
+// to support tickets. Every attempt is a separate entry, so retries show up as such.
+//
+// The Netskope-Api-Token header, IPSec pre-shared keys ("psk") and publisher registration
+// tokens ("token") are redacted from the log.
+//
+//	tracer := nsgo.NewHARFileTracer("nsgo.har")
+//	nsclient, err := nsgo.New(baseURL, nsgo.WithAPIToken(token), nsgo.WithHARTracer(tracer))
+type HARTracer struct {
+	path string
+	w    io.Writer
+
+	mu      sync.Mutex
+	entries []HAREntry
+	size    int64 // bytes of the HAR file written before its trailer
+	err     error // first error writing the HAR file
+}
+
+// harTrailer closes the entries of a HAR file, and the document.
+const harTrailer = "\n]}}\n"
+
+// NewHARTracer returns a HARTracer keeping its entries in memory until Flush writes them to w.
+func NewHARTracer(w io.Writer) *HARTracer {
+	return &HARTracer{w: w}
+}
+
+// NewHARFileTracer returns a HARTracer appending every entry to the file at path as it is traced,
+// so that the file is a complete HAR log even if the program stops. Entries are not kept in memory.
+func NewHARFileTracer(path string) *HARTracer {
+	return &HARTracer{path: path}
+}
+
+// WithHARTracer traces every request sent by the client with t.
+func WithHARTracer(t *HARTracer) Option {
+	return func(o *clientOptions) error {
+		if t == nil {
+			return errors.New("nsgo: nil HAR tracer")
+		}
+		o.harTracer = t
+		return nil
+	}
+}
+
+// WithHARFile traces every request sent by the client to a HAR file at path.
+func WithHARFile(path string) Option {
+	return WithHARTracer(NewHARFileTracer(path))
+}
+
+// Entries returns the entries traced since the last Flush. It returns none for a tracer of
+// NewHARFileTracer, whose entries are in the file.
+func (t *HARTracer) Entries() []HAREntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]HAREntry(nil), t.entries...)
+}
+
+// Flush writes the entries traced since the last Flush to the writer of t, as a complete HAR log,
+// and forgets them. Each Flush thus writes a new document holding only the entries that are new,
+// rather than appending to the previous one: give the tracer a writer that expects that, such as
+// one file per Flush.
+//
+// For a tracer of NewHARFileTracer, which writes its file as it goes, Flush returns the first error
+// met writing the file, if any.
+func (t *HARTracer) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.path != "" {
+		return t.err
+	}
+	data, err := json.MarshalIndent(HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: harCreator,
+		Entries: t.entries,
+	}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := t.w.Write(data); err != nil {
+		return err
+	}
+	t.entries = nil
+	return nil
+}
+
+func (t *HARTracer) add(entry HAREntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.path == "" {
+		t.entries = append(t.entries, entry)
+		return
+	}
+	//Tracing is best effort and must not fail the request; Flush reports the error.
+	if err := t.writeFile(entry); err != nil && t.err == nil {
+		t.err = err
+	}
+}
+
+// writeFile writes entry over the trailer of the HAR file, followed by the trailer again,
+// starting the file on the first entry.
+func (t *HARTracer) writeFile(entry HAREntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	var buf []byte
+	if t.size == 0 {
+		creator, err := json.Marshal(harCreator)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, `{"log":{"version":"1.2","creator":`...)
+		buf = append(buf, creator...)
+		buf = append(buf, `,"entries":[`+"\n"...)
+	} else {
+		buf = append(buf, ",\n"...)
+	}
+	buf = append(buf, data...)
+
+	f, err := os.OpenFile(t.path, os.O_WRONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if t.size == 0 {
+		err = f.Truncate(0)
+	}
+	if err == nil {
+		_, err = f.WriteAt(append(buf, harTrailer...), t.size)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	t.size += int64(len(buf))
+	return nil
+}
+
+var harCreator = HARCreator{Name: "nsgo", Version: defaultUserAgent}
+
+// HAR is the root of a HAR document.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the log of a HAR document.
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator identifies the program that wrote a HAR document.
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is a single request and its response.
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	// Attempt is the number of the attempt, starting at 1, when the client retries.
+	Attempt int `json:"_attempt,omitempty"`
+	// Error is the error that prevented getting a response, if any.
+	Error string `json:"_error,omitempty"`
+}
+
+// HARRequest is a request of a HAREntry.
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARResponse is a response of a HAREntry.
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARNameValue is a header, cookie or query parameter.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is the body of a request.
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent is the body of a response.
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// HARTimings breaks down the time spent on an entry, in milliseconds. -1 means the phase did not apply.
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harTransport traces the requests sent through next to tracer.
+type harTransport struct {
+	next   http.RoundTripper
+	tracer *HARTracer
+}
+
+func (t *harTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := HAREntry{StartedDateTime: time.Now(), Attempt: attemptOf(req)}
+
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	entry.Request = harRequest(req, reqBody)
+
+	var timer harTimer
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timer.trace()))
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		timer.done = time.Now()
+		entry.Error = err.Error()
+		entry.Response = HARResponse{Cookies: []HARNameValue{}, Headers: []HARNameValue{}, HeadersSize: -1, BodySize: -1}
+		entry.Timings, entry.Time = timer.timings(entry.StartedDateTime)
+		t.tracer.add(entry)
+		return nil, err
+	}
+
+	resBody, readErr := io.ReadAll(res.Body)
+	res.Body.Close()
+	timer.done = time.Now()
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	entry.Response = harResponse(res, resBody)
+	if readErr != nil {
+		entry.Error = readErr.Error()
+	}
+	entry.Timings, entry.Time = timer.timings(entry.StartedDateTime)
+	t.tracer.add(entry)
+	if readErr != nil {
+		return nil, readErr
+	}
+	return res, nil
+}
+
+func harRequest(req *http.Request, body []byte) HARRequest {
+	r := HARRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(req.Header),
+		QueryString: []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	for name, values := range req.URL.Query() {
+		for _, v := range values {
+			r.QueryString = append(r.QueryString, HARNameValue{Name: name, Value: v})
+		}
+	}
+	if len(body) > 0 {
+		r.PostData = &HARPostData{MimeType: req.Header.Get("Content-Type"), Text: string(redact.JSON(body))}
+	}
+	return r
+}
+
+func harResponse(res *http.Response, body []byte) HARResponse {
+	return HARResponse{
+		Status:      res.StatusCode,
+		StatusText:  http.StatusText(res.StatusCode),
+		HTTPVersion: res.Proto,
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(res.Header),
+		Content: HARContent{
+			Size:     len(body),
+			MimeType: res.Header.Get("Content-Type"),
+			Text:     string(redact.JSON(body)),
+		},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+func harHeaders(h http.Header) []HARNameValue {
+	headers := []HARNameValue{}
+	for name, values := range redact.Header(h) {
+		for _, v := range values {
+			headers = append(headers, HARNameValue{Name: name, Value: v})
+		}
+	}
+	return headers
+}
+
+// harTimer records the phases of a request with an httptrace.ClientTrace.
+type harTimer struct {
+	mu                     sync.Mutex
+	dnsStart, dnsDone      time.Time
+	connectStart, connDone time.Time
+	tlsStart, tlsDone      time.Time
+	gotConn, wrote, first  time.Time
+	done                   time.Time
+}
+
+func (t *harTimer) set(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if field.IsZero() {
+		*field = time.Now()
+	}
+}
+
+func (t *harTimer) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.set(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.set(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.set(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.set(&t.connDone) },
+		TLSHandshakeStart:    func() { t.set(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.set(&t.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { t.set(&t.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.set(&t.wrote) },
+		GotFirstResponseByte: func() { t.set(&t.first) },
+	}
+}
+
+// timings returns the phases of the request started at start, and their total, in milliseconds.
+func (t *harTimer) timings(start time.Time) (HARTimings, float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ms := func(from, to time.Time) float64 {
+		if from.IsZero() || to.IsZero() {
+			return -1
+		}
+		return float64(to.Sub(from)) / float64(time.Millisecond)
+	}
+	timings := HARTimings{
+		Blocked: -1,
+		DNS:     ms(t.dnsStart, t.dnsDone),
+		Connect: ms(t.connectStart, t.connDone),
+		SSL:     ms(t.tlsStart, t.tlsDone),
+		Send:    ms(t.gotConn, t.wrote),
+		Wait:    ms(t.wrote, t.first),
+		Receive: ms(t.first, t.done),
+	}
+	if !t.gotConn.IsZero() {
+		//Time spent before the connection, other than dialing, is time spent waiting for one.
+		blocked := ms(start, t.gotConn)
+		for _, phase := range []float64{timings.DNS, timings.Connect} {
+			if phase > 0 {
+				blocked -= phase
+			}
+		}
+		if blocked >= 0 {
+			timings.Blocked = blocked
+		}
+	}
+	//HAR requires send, wait and receive.
+	for _, phase := range []*float64{&timings.Send, &timings.Wait, &timings.Receive} {
+		if *phase < 0 {
+			*phase = 0
+		}
+	}
+	return timings, ms(start, t.done)
+}
@@ -0,0 +1,113 @@
+package nsgo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestHARTracer(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	var buf bytes.Buffer
+	tracer := nsgo.NewHARTracer(&buf)
+	nsclient := srv.Client(nsgo.WithHARTracer(tracer), nsgo.WithRetry(nsgo.RetryConfig{RetryMax: 2}))
+	ctx := context.Background()
+
+	srv.Fail(nsgotest.Failure{Path: "/api/v2/steering/ipsec/pops", Status: http.StatusServiceUnavailable})
+	if _, err := nsclient.IPsec.ListPops(ctx); err != nil {
+		t.Fatalf("IPsec.ListPops: %v", err)
+	}
+	if _, err := nsclient.IPsec.CreateTunnel(ctx, nsgo.NewIpsecTunnel{Site: "branch-1", Psk: "s3cr3t-psk"}); err != nil {
+		t.Fatalf("IPsec.CreateTunnel: %v", err)
+	}
+	pub, err := nsclient.Publishers.Create(ctx, nsgo.PublisherOptions{Name: "pub-1"})
+	if err != nil {
+		t.Fatalf("Publishers.Create: %v", err)
+	}
+	token, err := nsclient.Publishers.Token(ctx, nsgo.PublisherOptions{Id: strconv.Itoa(pub.ID)})
+	if err != nil {
+		t.Fatalf("Publishers.Token: %v", err)
+	}
+
+	entries := tracer.Entries()
+	if len(entries) != 5 {
+		t.Fatalf("traced %d entries, want 5", len(entries))
+	}
+	if entries[0].Response.Status != 503 || entries[0].Attempt != 1 || entries[1].Response.Status != http.StatusOK || entries[1].Attempt != 2 {
+		t.Errorf("retry entries = %+v, %+v", entries[0].Response, entries[1].Response)
+	}
+	if entries[2].Response.Status != http.StatusCreated || entries[2].Attempt != 1 {
+		t.Errorf("Attempt of a new call = %d, want 1", entries[2].Attempt)
+	}
+
+	if err := tracer.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	var har nsgo.HAR
+	if err := json.Unmarshal(buf.Bytes(), &har); err != nil || har.Log.Version != "1.2" || len(har.Log.Entries) != 5 {
+		t.Fatalf("Flush wrote an invalid HAR log (%v):\n%s", err, buf.Bytes())
+	}
+	for _, secret := range []string{nsgotest.DefaultToken, "s3cr3t-psk", token.Token} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("HAR log contains %q", secret)
+		}
+	}
+
+	// Flushed entries are forgotten; the next Flush writes a new log with the entries since.
+	if n := len(tracer.Entries()); n != 0 {
+		t.Errorf("%d entries kept after Flush, want 0", n)
+	}
+	buf.Reset()
+	if _, err := nsclient.IPsec.ListPops(ctx); err != nil {
+		t.Fatalf("IPsec.ListPops: %v", err)
+	}
+	if err := tracer.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &har); err != nil || len(har.Log.Entries) != 1 {
+		t.Errorf("second Flush wrote %d entries (%v), want 1", len(har.Log.Entries), err)
+	}
+}
+
+func TestHARFile(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "nsgo.har")
+	tracer := nsgo.NewHARFileTracer(path)
+	nsclient := srv.Client(nsgo.WithHARTracer(tracer))
+
+	// The file is a complete log after every entry.
+	var har nsgo.HAR
+	for i := 1; i <= 3; i++ {
+		if _, err := nsclient.IPsec.ListPops(context.Background()); err != nil {
+			t.Fatalf("IPsec.ListPops: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, &har); err != nil || har.Log.Version != "1.2" || len(har.Log.Entries) != i {
+			t.Fatalf("HAR file after %d requests = %s (%v)", i, data, err)
+		}
+	}
+	if n := len(tracer.Entries()); n != 0 {
+		t.Errorf("file tracer kept %d entries in memory, want 0", n)
+	}
+	if err := tracer.Flush(); err != nil {
+		t.Errorf("Flush: %v", err)
+	}
+	entry := har.Log.Entries[0]
+	if entry.Request.Method != "GET" || !strings.HasSuffix(entry.Request.URL, "/api/v2/steering/ipsec/pops") || entry.Response.Content.Size == 0 || entry.Time <= 0 {
+		t.Errorf("entry = %+v", entry)
+	}
+}
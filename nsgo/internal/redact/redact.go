@@ -0,0 +1,92 @@
+// Package redact removes secrets from the requests and responses exchanged with a tenant,
+// so that they can be written to fixtures and traces.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Placeholder replaces the redacted values.
+const Placeholder = "REDACTED"
+
+// headers are the headers carrying credentials.
+var headers = []string{"Netskope-Api-Token", "Authorization", "Cookie", "Set-Cookie"}
+
+// fields are the JSON object keys holding secrets: IPSec pre-shared keys and publisher registration tokens.
+var fields = map[string]bool{"psk": true, "token": true}
+
+// Header returns a copy of h with the credential headers redacted.
+func Header(h http.Header) http.Header {
+	out := h.Clone()
+	if out == nil {
+		return http.Header{}
+	}
+	for _, k := range headers {
+		if _, ok := out[http.CanonicalHeaderKey(k)]; ok {
+			out.Set(k, Placeholder)
+		}
+	}
+	return out
+}
+
+// IsSecretHeader reports whether the header named key carries credentials.
+func IsSecretHeader(key string) bool {
+	for _, k := range headers {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON returns body with the secret fields of the JSON objects it holds, at any depth, redacted.
+// Bodies that are not JSON are returned unchanged.
+func JSON(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !value(v) {
+		return body
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// value redacts the secret fields in v and reports whether it changed anything.
+func value(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if fields[strings.ToLower(k)] {
+				if s, ok := field.(string); ok && s != "" && s != Placeholder {
+					v[k] = Placeholder
+					changed = true
+				}
+				continue
+			}
+			if value(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if value(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,35 @@
+package redact
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{"site":"branch-1","psk":"secret"}`, `{"psk":"REDACTED","site":"branch-1"}`},
+		{`{"status":"success","data":{"token":"abc"}}`, `{"data":{"token":"REDACTED"},"status":"success"}`},
+		{`{"result":[{"psk":"a"},{"id":1}]}`, `{"result":[{"psk":"REDACTED"},{"id":1}]}`},
+		{`{"id":12345678901234567890}`, `{"id":12345678901234567890}`},
+		{`not json`, `not json`},
+		{``, ``},
+	}
+	for _, tt := range tests {
+		if got := string(JSON([]byte(tt.in))); got != tt.want {
+			t.Errorf("JSON(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHeader(t *testing.T) {
+	h := http.Header{"Netskope-Api-Token": {"secret"}, "Content-Type": {"application/json"}}
+	got := Header(h)
+	if got.Get("Netskope-Api-Token") != Placeholder || got.Get("Content-Type") != "application/json" {
+		t.Errorf("Header = %v", got)
+	}
+	if h.Get("Netskope-Api-Token") != "secret" {
+		t.Error("Header modified its argument")
+	}
+}
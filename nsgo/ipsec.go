@@ -2,18 +2,20 @@ package nsgo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/go-querystring/query"
 )
 
 //IpsecPops defines a struct used for list of Netskope IPSec PoPs returned from the tenant.
-type IpsecPops []struct {
+type IpsecPops []IpsecPop
+
+//IpsecPop defines an individual Netskope IPSec PoP.
+type IpsecPop struct {
 	Gateway  string       `json:"gateway"`
 	ID       string       `json:"id"`
 	Location string       `json:"location"`
@@ -49,50 +51,42 @@ type IpsecPhase2 struct {
 }
 
 //IpsecTunnels defines a struct to return a list of IPSec tunnels.
-type IpsecTunnels []struct {
-	ID      int    `json:"id"`
-	Site    string `json:"site"`
-	Enabled bool   `json:"enabled"`
-	Pops    []struct {
-		Name    string `json:"name"`
-		Gateway string `json:"gateway"`
-		Probeip string `json:"probeip"`
-		Primary bool   `json:"primary"`
-	} `json:"pops"`
-	Status struct {
-		Status     string `json:"status"`
-		Since      string `json:"since"`
-		Throughput string `json:"throughput"`
-	} `json:"status"`
-	Template      string `json:"template"`
-	Sourcetype    string `json:"sourcetype"`
-	Notes         string `json:"notes"`
-	Encryption    string `json:"encryption"`
-	Srcidentity   string `json:"srcidentity"`
-	Srcipidentity string `json:"srcipidentity"`
-}
+type IpsecTunnels []IpsecTunnel
 
+//IpsecTunnel defines an individual IPSec tunnel.
 type IpsecTunnel struct {
-	ID      int    `json:"id"`
-	Site    string `json:"site"`
-	Enabled bool   `json:"enabled"`
-	Pops    []struct {
-		Name    string `json:"name"`
-		Gateway string `json:"gateway"`
-		Probeip string `json:"probeip"`
-		Primary bool   `json:"primary"`
-	} `json:"pops"`
-	Status struct {
-		Status     string `json:"status"`
-		Since      string `json:"since"`
-		Throughput string `json:"throughput"`
-	} `json:"status"`
-	Template      string `json:"template"`
-	Sourcetype    string `json:"sourcetype"`
-	Notes         string `json:"notes"`
-	Encryption    string `json:"encryption"`
-	Srcidentity   string `json:"srcidentity"`
-	Srcipidentity string `json:"srcipidentity"`
+	ID            int               `json:"id"`
+	Site          string            `json:"site"`
+	Enabled       bool              `json:"enabled"`
+	Pops          []IpsecTunnelPop  `json:"pops"`
+	Status        IpsecTunnelStatus `json:"status"`
+	Template      string            `json:"template"`
+	Sourcetype    string            `json:"sourcetype"`
+	Notes         string            `json:"notes"`
+	Encryption    string            `json:"encryption"`
+	Srcidentity   string            `json:"srcidentity"`
+	Srcipidentity string            `json:"srcipidentity"`
+}
+
+//IpsecTunnelPop defines a PoP an IPSec tunnel is attached to.
+type IpsecTunnelPop struct {
+	Name    string `json:"name"`
+	Gateway string `json:"gateway"`
+	Probeip string `json:"probeip"`
+	Primary bool   `json:"primary"`
+}
+
+//IpsecTunnelStatus defines the state of an IPSec tunnel.
+type IpsecTunnelStatus struct {
+	Status     string `json:"status"`
+	Since      string `json:"since"`
+	Throughput string `json:"throughput"`
+}
+
+//The tunnel status is sent as null or {} for tunnels that never came up.
+func (s *IpsecTunnelStatus) UnmarshalJSON(data []byte) error {
+	type plain IpsecTunnelStatus
+	return decodeObject(data, (*plain)(s))
 }
 
 //NewIpsecTunnel defines a struct for creating an IPSec tunnel in Netskope.
@@ -106,39 +100,23 @@ type NewIpsecTunnel struct {
 	Sourcetype    string        `json:"sourcetype,omitempty"` //['User', 'Server', 'IoT', 'Guest wifi', 'Mixed']
 	Pops          []interface{} `json:"pops,omitempty"`
 	Bandwidth     int           `json:"bandwidth,omitempty"` //[50, 100, 150, 250]
-	Enable        bool          `json:"enable,omitempty"`
+	Enable        *bool         `json:"enable,omitempty"`
 }
 
-func (c *Client) GetIpsecPops() (*IpsecPops, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/steering/ipsec/pops", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
+// ipsecService implements IPsecService for a Client.
+type ipsecService struct {
+	c *Client
+}
 
-	res := ipsecResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+func (s *ipsecService) ListPops(ctx context.Context) (*IpsecPops, error) {
+	pops, err := doJSON[IpsecPops](ctx, s.c, "GET", "/api/v2/steering/ipsec/pops", nil)
+	if err != nil {
 		return nil, err
 	}
-
-	if res.Status == 200 {
-		jsonData, err := json.Marshal(res.Result)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := IpsecPops{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-
-		//return res.Result, nil
-		//} else if res.Status == "error" {
-		//	return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + strconv.Itoa(res.Status))
-	}
+	return &pops, nil
 }
 
-func (c *Client) GetIpsecPopsWithFilters(filters PopFilters) (*IpsecPops, error) {
+func (s *ipsecService) ListPopsWithFilters(ctx context.Context, filters PopFilters) (*IpsecPops, error) {
 	//Validate the Filters Struct
 	//https://go.dev/play/p/rk40YsfJkaI
 	var validate *validator.Validate
@@ -155,224 +133,199 @@ func (c *Client) GetIpsecPopsWithFilters(filters PopFilters) (*IpsecPops, error)
 		return nil, err
 	}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/steering/ipsec/pops?%s", c.BaseURL, filter_query.Encode()), nil)
+	pops, err := doJSON[IpsecPops](ctx, s.c, "GET", "/api/v2/steering/ipsec/pops?"+filter_query.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
-
-	res := ipsecResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
-
-	if res.Status == 200 {
-		jsonData, err := json.Marshal(res.Result)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := IpsecPops{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-
-		//return res.Result, nil
-		//} else if res.Status == "error" {
-		//	return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + strconv.Itoa(res.Status))
-	}
+	return &pops, nil
 }
 
-//GetIpsecPopId function is used to GET an individual Pop by ID.
-func (c *Client) GetIpsecPopId(options RequestOptions) (*IpsecPops, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/steering/ipsec/pops/%s", c.BaseURL, options.Id), nil)
+func (s *ipsecService) GetPop(ctx context.Context, options RequestOptions) (*IpsecPop, error) {
+	return doIpsecObject[IpsecPop](ctx, s.c, "GET", "/api/v2/steering/ipsec/pops/"+options.Id, nil)
+}
 
+func (s *ipsecService) ListTunnels(ctx context.Context) (*IpsecTunnels, error) {
+	tunnels, err := doJSON[IpsecTunnels](ctx, s.c, "GET", "/api/v2/steering/ipsec/tunnels", nil)
 	if err != nil {
 		return nil, err
 	}
+	return &tunnels, nil
+}
 
-	res := ipsecResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+func (s *ipsecService) TunnelPages(opts ListOptions) *Pager[IpsecTunnel] {
+	return NewPager(opts, listFetcher(s.c, "/api/v2/steering/ipsec/tunnels", func(l *IpsecTunnels) []IpsecTunnel {
+		return *l
+	}))
+}
+
+func (s *ipsecService) GetTunnel(ctx context.Context, options RequestOptions) (*IpsecTunnel, error) {
+	return doIpsecObject[IpsecTunnel](ctx, s.c, "GET", "/api/v2/steering/ipsec/tunnels/"+options.Id, nil)
+}
 
-	if res.Status == 200 {
-		jsonData, err := json.Marshal(res.Result)
+func (s *ipsecService) CreateTunnel(ctx context.Context, ipsectunnel NewIpsecTunnel) (*IpsecTunnel, error) {
+	return verifiedCreate(ctx, s.c, func(ctx context.Context) (*IpsecTunnel, error) {
+		return doIpsecObject[IpsecTunnel](ctx, s.c, "POST", "/api/v2/steering/ipsec/tunnels", ipsectunnel)
+	}, func(ctx context.Context) (*IpsecTunnel, error) {
+		tunnels, err := s.ListTunnels(ctx)
 		if err != nil {
 			return nil, err
 		}
-		dataStruct := IpsecPops{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
+		for _, t := range *tunnels {
+			if t.Site == ipsectunnel.Site {
+				return &t, nil
+			}
+		}
+		return nil, nil
+	})
+}
 
-		//return res.Result, nil
-	} else {
-		return nil, errors.New("Unkown Status: " + strconv.Itoa(res.Status))
-	}
+func (s *ipsecService) UpdateTunnel(ctx context.Context, options RequestOptions, ipsectunnel NewIpsecTunnel) (*IpsecTunnel, error) {
+	return doIpsecObject[IpsecTunnel](ctx, s.c, "PATCH", "/api/v2/steering/ipsec/tunnels/"+options.Id, ipsectunnel)
 }
 
-//GetIpsecTunnels defines a function to get a list of IPSec Tunnels from a Netskope tenant.
-func (c *Client) GetIpsecTunnels() (*IpsecTunnels, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/steering/ipsec/tunnels", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
+func (s *ipsecService) DeleteTunnel(ctx context.Context, options RequestOptions) (*IpsecTunnel, error) {
+	return doIpsecObject[IpsecTunnel](ctx, s.c, "DELETE", "/api/v2/steering/ipsec/tunnels/"+options.Id, nil)
+}
 
-	res := ipsecResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+//GetIpsecPops defines a function to get the list of Netskope IPSec PoPs.
+//
+//Deprecated: Use c.IPsec.ListPops.
+func (c *Client) GetIpsecPops() (*IpsecPops, error) {
+	return c.IPsec.ListPops(context.Background())
+}
 
-	if res.Status == 200 {
-		jsonData, err := json.Marshal(res.Result)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := IpsecTunnels{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-
-		//return res.Result, nil
-		//} else if res.Status == "error" {
-		//	return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + strconv.Itoa(res.Status))
-	}
+// GetIpsecPopsWithContext is like GetIpsecPops but uses ctx for the request.
+// It forwards to c.IPsec.ListPops.
+func (c *Client) GetIpsecPopsWithContext(ctx context.Context) (*IpsecPops, error) {
+	return c.IPsec.ListPops(ctx)
 }
 
-//GetIpsecTunnelId function is used to GET an individual Tunnel by ID.
-func (c *Client) GetIpsecTunnelId(options RequestOptions) (*IpsecTunnels, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/steering/ipsec/tunnels/%s", c.BaseURL, options.Id), nil)
+//Deprecated: Use c.IPsec.ListPopsWithFilters.
+func (c *Client) GetIpsecPopsWithFilters(filters PopFilters) (*IpsecPops, error) {
+	return c.IPsec.ListPopsWithFilters(context.Background(), filters)
+}
 
-	if err != nil {
-		return nil, err
-	}
+// GetIpsecPopsWithFiltersWithContext is like GetIpsecPopsWithFilters but uses ctx for the request.
+// It forwards to c.IPsec.ListPopsWithFilters.
+func (c *Client) GetIpsecPopsWithFiltersWithContext(ctx context.Context, filters PopFilters) (*IpsecPops, error) {
+	return c.IPsec.ListPopsWithFilters(ctx, filters)
+}
 
-	res := ipsecResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+//GetIpsecPopId function is used to GET an individual Pop by ID.
+//
+//Deprecated: Use c.IPsec.GetPop.
+func (c *Client) GetIpsecPopId(options RequestOptions) (*IpsecPop, error) {
+	return c.IPsec.GetPop(context.Background(), options)
+}
 
-	if res.Status == 200 {
-		jsonData, err := json.Marshal(res.Result)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := IpsecTunnels{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-
-		//return res.Result, nil
-		//} else if res.Status == "error" {
-		//	return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + strconv.Itoa(res.Status))
-	}
+// GetIpsecPopIdWithContext is like GetIpsecPopId but uses ctx for the request.
+// It forwards to c.IPsec.GetPop.
+func (c *Client) GetIpsecPopIdWithContext(ctx context.Context, options RequestOptions) (*IpsecPop, error) {
+	return c.IPsec.GetPop(ctx, options)
 }
 
-//CreateIpsecTunnel defines a function to create a new IPSec Tunnel in a Netskope tennant.
-func (c *Client) CreateIpsecTunnel(ipsectunnel NewIpsecTunnel) (interface{}, error) {
-	json_body, err := json.Marshal(ipsectunnel)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
+//GetIpsecTunnels defines a function to get a list of IPSec Tunnels from a Netskope tenant.
+//
+//Deprecated: Use c.IPsec.ListTunnels.
+func (c *Client) GetIpsecTunnels() (*IpsecTunnels, error) {
+	return c.IPsec.ListTunnels(context.Background())
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/steering/ipsec/tunnels", c.BaseURL), bytes.NewBuffer(json_body))
-	if err != nil {
-		return nil, err
-	}
+// GetIpsecTunnelsWithContext is like GetIpsecTunnels but uses ctx for the request.
+// It forwards to c.IPsec.ListTunnels.
+func (c *Client) GetIpsecTunnelsWithContext(ctx context.Context) (*IpsecTunnels, error) {
+	return c.IPsec.ListTunnels(ctx)
+}
 
-	res := ipsecResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+//GetIpsecTunnelId function is used to GET an individual Tunnel by ID.
+//
+//Deprecated: Use c.IPsec.GetTunnel.
+func (c *Client) GetIpsecTunnelId(options RequestOptions) (*IpsecTunnel, error) {
+	return c.IPsec.GetTunnel(context.Background(), options)
+}
 
-	if res.Status == 201 {
-		/*
-			jsonData, err := json.Marshal(res.Result)
-			if err != nil {
-				return nil, err
-			}
-			dataStruct := IpsecTunnels{}
-			json.Unmarshal(jsonData, &dataStruct)
-			return &dataStruct, nil
-		*/
-		return res.Result, nil
-		//} else if res.Status == "error" {
-		//	return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + strconv.Itoa(res.Status))
-	}
+// GetIpsecTunnelIdWithContext is like GetIpsecTunnelId but uses ctx for the request.
+// It forwards to c.IPsec.GetTunnel.
+func (c *Client) GetIpsecTunnelIdWithContext(ctx context.Context, options RequestOptions) (*IpsecTunnel, error) {
+	return c.IPsec.GetTunnel(ctx, options)
 }
 
-//UpdateIpsecTunnel defines a function to create a new IPSec Tunnel in a Netskope tennant.
-func (c *Client) UpdateIpsecTunnel(options RequestOptions, ipsectunnel NewIpsecTunnel) (interface{}, error) {
-	json_body, err := json.Marshal(ipsectunnel)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
+//CreateIpsecTunnel defines a function to create a new IPSec Tunnel in a Netskope tennant.
+//It returns the created tunnel.
+//
+//Deprecated: Use c.IPsec.CreateTunnel.
+func (c *Client) CreateIpsecTunnel(ipsectunnel NewIpsecTunnel) (*IpsecTunnel, error) {
+	return c.IPsec.CreateTunnel(context.Background(), ipsectunnel)
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/api/v2/steering/ipsec/tunnels/%s", c.BaseURL, options.Id), bytes.NewBuffer(json_body))
-	if err != nil {
-		return nil, err
-	}
+// CreateIpsecTunnelWithContext is like CreateIpsecTunnel but uses ctx for the request.
+// It forwards to c.IPsec.CreateTunnel.
+func (c *Client) CreateIpsecTunnelWithContext(ctx context.Context, ipsectunnel NewIpsecTunnel) (*IpsecTunnel, error) {
+	return c.IPsec.CreateTunnel(ctx, ipsectunnel)
+}
 
-	res := ipsecResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+//UpdateIpsecTunnel defines a function to update an IPSec Tunnel in a Netskope tennant.
+//It returns the updated tunnel.
+//
+//Deprecated: Use c.IPsec.UpdateTunnel.
+func (c *Client) UpdateIpsecTunnel(options RequestOptions, ipsectunnel NewIpsecTunnel) (*IpsecTunnel, error) {
+	return c.IPsec.UpdateTunnel(context.Background(), options, ipsectunnel)
+}
 
-	if res.Status == 200 {
-		/*
-			jsonData, err := json.Marshal(res.Result)
-			if err != nil {
-				return nil, err
-			}
-			dataStruct := IpsecTunnels{}
-			json.Unmarshal(jsonData, &dataStruct)
-			return &dataStruct, nil
-		*/
-		return res.Result, nil
-		//} else if res.Status == "error" {
-		//	return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + strconv.Itoa(res.Status))
-		//return nil, errors.New("Unkown Status: " + res)
-	}
+// UpdateIpsecTunnelWithContext is like UpdateIpsecTunnel but uses ctx for the request.
+// It forwards to c.IPsec.UpdateTunnel.
+func (c *Client) UpdateIpsecTunnelWithContext(ctx context.Context, options RequestOptions, ipsectunnel NewIpsecTunnel) (*IpsecTunnel, error) {
+	return c.IPsec.UpdateTunnel(ctx, options, ipsectunnel)
 }
 
-//DeleteIpsecTunnel defines a function to create a new IPSec Tunnel in a Netskope tennant.
-func (c *Client) DeleteIpsecTunnel(options RequestOptions) (interface{}, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/v2/steering/ipsec/tunnels/%s", c.BaseURL, options.Id), nil)
-	if err != nil {
-		return nil, err
-	}
+//DeleteIpsecTunnel defines a function to delete an IPSec Tunnel in a Netskope tennant.
+//It returns the deleted tunnel when the API echoes it, and an empty tunnel otherwise.
+//
+//Deprecated: Use c.IPsec.DeleteTunnel.
+func (c *Client) DeleteIpsecTunnel(options RequestOptions) (*IpsecTunnel, error) {
+	return c.IPsec.DeleteTunnel(context.Background(), options)
+}
 
-	res := ipsecResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+// DeleteIpsecTunnelWithContext is like DeleteIpsecTunnel but uses ctx for the request.
+// It forwards to c.IPsec.DeleteTunnel.
+func (c *Client) DeleteIpsecTunnelWithContext(ctx context.Context, options RequestOptions) (*IpsecTunnel, error) {
+	return c.IPsec.DeleteTunnel(ctx, options)
+}
+
+//doIpsecObject sends a request to an IPSec endpoint returning a single object. Those endpoints
+//send the object either on its own or as the only item of a list. An empty list is reported as
+//an APIError matching ErrNotFound for GET requests, and as the zero value of T for the others,
+//i.e. a delete that does not echo the tunnel.
+func doIpsecObject[T any](ctx context.Context, c *Client, method, path string, body interface{}) (*T, error) {
+	payload, _, err := c.do(ctx, method, path, body)
+	if err != nil {
 		return nil, err
 	}
 
-	if res.Status == 200 {
-		/*
-			jsonData, err := json.Marshal(res.Result)
-			if err != nil {
-				return nil, err
+	var out T
+	payload = bytes.TrimSpace(payload)
+	if len(payload) > 0 && payload[0] == '[' {
+		var list []T
+		if err := json.Unmarshal(payload, &list); err != nil {
+			return nil, fmt.Errorf("nsgo: decoding %s %s response: %w", method, path, err)
+		}
+		if len(list) == 0 && method == http.MethodGet {
+			return nil, &APIError{
+				StatusCode: http.StatusNotFound,
+				Method:     method,
+				Path:       stripQuery(path),
+				Message:    "no object in the response",
+				Body:       payload,
+				RateLimit:  RateLimitInfo{Limit: -1, Remaining: -1},
 			}
-			dataStruct := IpsecTunnels{}
-			json.Unmarshal(jsonData, &dataStruct)
-			return &dataStruct, nil
-		*/
-		return res.Result, nil
-		//} else if res.Status == "error" {
-		//	return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + strconv.Itoa(res.Status))
+		}
+		if len(list) == 0 {
+			return &out, nil
+		}
+		return &list[0], nil
+	}
+	if err := decodePayload(payload, &out); err != nil {
+		return nil, fmt.Errorf("nsgo: decoding %s %s response: %w", method, path, err)
 	}
+	return &out, nil
 }
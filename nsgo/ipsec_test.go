@@ -0,0 +1,125 @@
+package nsgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestIpsecPops(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+
+	pops, err := nsclient.GetIpsecPops()
+	if err != nil {
+		t.Fatalf("GetIpsecPops: %v", err)
+	}
+	if len(*pops) != 2 {
+		t.Fatalf("GetIpsecPops returned %d pops, want 2", len(*pops))
+	}
+
+	filtered, err := nsclient.GetIpsecPopsWithFilters(nsgo.PopFilters{Country: "DE"})
+	if err != nil {
+		t.Fatalf("GetIpsecPopsWithFilters: %v", err)
+	}
+	if len(*filtered) != 1 || (*filtered)[0].Name != "DE-FRA1" {
+		t.Errorf("GetIpsecPopsWithFilters = %+v", *filtered)
+	}
+
+	if _, err := nsclient.GetIpsecPopsWithFilters(nsgo.PopFilters{Name: "US-SJC1", Region: "US"}); err == nil {
+		t.Error("GetIpsecPopsWithFilters accepted conflicting filters")
+	}
+
+	pop, err := nsclient.GetIpsecPopId(nsgo.RequestOptions{Id: "1"})
+	if err != nil {
+		t.Fatalf("GetIpsecPopId: %v", err)
+	}
+	if pop.Name != "US-SJC1" || pop.Options.Phase1.Ikeversion != "2" {
+		t.Errorf("GetIpsecPopId = %+v", pop)
+	}
+}
+
+func TestIpsecTunnels(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+
+	created, err := nsclient.CreateIpsecTunnel(nsgo.NewIpsecTunnel{
+		Site:        "branch-1",
+		Encryption:  "AES256-CBC",
+		Srcidentity: "branch-1.example.com",
+		Psk:         "secret",
+		Sourcetype:  "User",
+		Pops:        []interface{}{"US-SJC1", "DE-FRA1"},
+		Bandwidth:   100,
+		Enable:      nsgo.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("CreateIpsecTunnel: %v", err)
+	}
+	if created.ID == 0 || created.Site != "branch-1" || len(created.Pops) != 2 || !created.Pops[0].Primary {
+		t.Fatalf("CreateIpsecTunnel = %+v", created)
+	}
+	id := strconv.Itoa(created.ID)
+
+	got, err := nsclient.GetIpsecTunnelId(nsgo.RequestOptions{Id: id})
+	if err != nil {
+		t.Fatalf("GetIpsecTunnelId: %v", err)
+	}
+	if got.ID != created.ID || got.Status.Status != "down" || got.Pops[0].Gateway != "163.116.128.80" {
+		t.Errorf("GetIpsecTunnelId = %+v", got)
+	}
+
+	updated, err := nsclient.UpdateIpsecTunnel(nsgo.RequestOptions{Id: id}, nsgo.NewIpsecTunnel{Enable: nsgo.Bool(false)})
+	if err != nil {
+		t.Fatalf("UpdateIpsecTunnel: %v", err)
+	}
+	if updated.Enabled || updated.Site != "branch-1" {
+		t.Errorf("UpdateIpsecTunnel = %+v", updated)
+	}
+
+	tunnels, err := nsclient.GetIpsecTunnels()
+	if err != nil {
+		t.Fatalf("GetIpsecTunnels: %v", err)
+	}
+	if len(*tunnels) != 1 {
+		t.Errorf("GetIpsecTunnels returned %d tunnels, want 1", len(*tunnels))
+	}
+
+	if _, err := nsclient.DeleteIpsecTunnel(nsgo.RequestOptions{Id: id}); err != nil {
+		t.Fatalf("DeleteIpsecTunnel: %v", err)
+	}
+	if _, err := nsclient.GetIpsecTunnelId(nsgo.RequestOptions{Id: id}); !errors.Is(err, nsgo.ErrNotFound) {
+		t.Errorf("GetIpsecTunnelId after delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIpsecTunnelEmptyResult(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":200,"result":[]}`))
+	}))
+	defer srv.Close()
+	nsclient, _ := nsgo.New(srv.URL)
+
+	tunnel, err := nsclient.IPsec.GetTunnel(context.Background(), nsgo.RequestOptions{Id: "42"})
+	var apiErr *nsgo.APIError
+	if !errors.Is(err, nsgo.ErrNotFound) || !errors.As(err, &apiErr) || tunnel != nil {
+		t.Fatalf("IPsec.GetTunnel = %+v, %v; want an APIError matching ErrNotFound", tunnel, err)
+	}
+	if apiErr.Path != "/api/v2/steering/ipsec/tunnels/42" {
+		t.Errorf("APIError.Path = %q", apiErr.Path)
+	}
+
+	// A delete that does not echo the tunnel succeeds.
+	tunnel, err = nsclient.IPsec.DeleteTunnel(context.Background(), nsgo.RequestOptions{Id: "42"})
+	if err != nil || tunnel == nil || tunnel.ID != 0 {
+		t.Errorf("IPsec.DeleteTunnel = %+v, %v; want an empty tunnel", tunnel, err)
+	}
+}
@@ -0,0 +1,98 @@
+package nsgo
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/internal/redact"
+)
+
+// LeveledLogger adapts a *slog.Logger to the LeveledLogger interface of go-retryablehttp,
+// redacting credentials from the values it is given. WithLogger and RetryConfig.Logger use
+// it for *slog.Logger values; it is exported for callers building their own retryablehttp clients.
+type LeveledLogger struct {
+	Logger *slog.Logger
+}
+
+// Error logs msg at the error level.
+func (l LeveledLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log(slog.LevelError, msg, keysAndValues)
+}
+
+// Warn logs msg at the warning level.
+func (l LeveledLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log(slog.LevelWarn, msg, keysAndValues)
+}
+
+// Info logs msg at the info level.
+func (l LeveledLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.log(slog.LevelInfo, msg, keysAndValues)
+}
+
+// Debug logs msg at the debug level.
+func (l LeveledLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log(slog.LevelDebug, msg, keysAndValues)
+}
+
+func (l LeveledLogger) log(level slog.Level, msg string, keysAndValues []interface{}) {
+	if l.Logger == nil {
+		return
+	}
+	args := make([]interface{}, len(keysAndValues))
+	copy(args, keysAndValues)
+	for i := 0; i+1 < len(args); i += 2 {
+		key, _ := args[i].(string)
+		switch v := args[i+1].(type) {
+		case http.Header:
+			args[i+1] = redact.Header(v)
+		default:
+			if redact.IsSecretHeader(key) || strings.Contains(strings.ToLower(key), "token") {
+				args[i+1] = redact.Placeholder
+			}
+		}
+	}
+	l.Logger.Log(context.Background(), level, msg, args...)
+}
+
+// discardHandler is a slog.Handler dropping every record, used when no logger is set.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// logTransport logs every attempt to send a request through next. Headers, and so the API token,
+// are never logged.
+type logTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempt := attemptOf(req)
+	if attempt > 1 {
+		t.logger.WarnContext(ctx, "nsgo: retrying request",
+			"method", req.Method, "path", req.URL.Path, "attempt", attempt)
+	}
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		t.logger.WarnContext(ctx, "nsgo: request failed",
+			"method", req.Method, "path", req.URL.Path, "attempt", attempt, "duration", duration, "error", err)
+		return nil, err
+	}
+
+	attrs := []interface{}{"method", req.Method, "path", req.URL.Path, "status", res.StatusCode, "duration", duration, "attempt", attempt}
+	if remaining := parseRateLimit(res.Header).Remaining; remaining >= 0 {
+		attrs = append(attrs, "ratelimit_remaining", remaining)
+	}
+	t.logger.DebugContext(ctx, "nsgo: request", attrs...)
+	return res, nil
+}
@@ -0,0 +1,102 @@
+package nsgo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+// records decodes the JSON log records written to buf.
+func records(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var out []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("decoding log record %q: %v", line, err)
+		}
+		out = append(out, record)
+	}
+	return out
+}
+
+func TestLogger(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	nsclient := srv.Client(nsgo.WithLogger(logger), nsgo.WithRetry(nsgo.RetryConfig{RetryMax: 2}))
+
+	srv.Fail(nsgotest.Failure{Path: "/api/v2/infrastructure/publishers", Status: http.StatusServiceUnavailable})
+	if _, err := nsclient.Publishers.List(context.Background()); err != nil {
+		t.Fatalf("Publishers.List: %v", err)
+	}
+
+	if strings.Contains(buf.String(), nsgotest.DefaultToken) {
+		t.Errorf("logs contain the API token:\n%s", buf.String())
+	}
+	var requests, retries, retryablehttp int
+	for _, r := range records(t, &buf) {
+		switch r["msg"] {
+		case "nsgo: request":
+			requests++
+			if r["level"] != "DEBUG" || r["method"] != "GET" || r["path"] != "/api/v2/infrastructure/publishers" || r["duration"] == nil {
+				t.Errorf("request record = %v", r)
+			}
+			if requests == 2 && (r["status"] != 200.0 || r["attempt"] != 2.0) {
+				t.Errorf("second request record = %v", r)
+			}
+		case "nsgo: retrying request":
+			retries++
+			if r["level"] != "WARN" || r["attempt"] != 2.0 {
+				t.Errorf("retry record = %v", r)
+			}
+		default:
+			// Records of the retrying client, i.e. "performing request".
+			retryablehttp++
+		}
+	}
+	if requests != 2 || retries != 1 || retryablehttp == 0 {
+		t.Errorf("got %d request, %d retry and %d retryablehttp records:\n%s", requests, retries, retryablehttp, buf.String())
+	}
+}
+
+func TestLoggerUnknownStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"pending","data":{}}`))
+	}))
+	defer srv.Close()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	nsclient, _ := nsgo.New(srv.URL, nsgo.WithLogger(logger))
+
+	if _, err := nsclient.PrivateApps.List(context.Background()); err == nil {
+		t.Fatal("PrivateApps.List succeeded with an unknown status")
+	}
+	found := false
+	for _, r := range records(t, &buf) {
+		if r["msg"] == "nsgo: unknown response status" && r["level"] == "WARN" && r["status"] == `"pending"` {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no warning for the unknown status:\n%s", buf.String())
+	}
+}
+
+func TestLeveledLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := nsgo.LeveledLogger{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+	l.Error("failed", "header", http.Header{"Netskope-Api-Token": {"secret"}}, "token", "secret", "url", "/api/v2")
+	if strings.Contains(buf.String(), "secret") || !strings.Contains(buf.String(), `"url":"/api/v2"`) {
+		t.Errorf("LeveledLogger wrote %s", buf.String())
+	}
+}
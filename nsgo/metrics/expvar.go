@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"strconv"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+)
+
+// Expvar is an nsgo.Observer publishing request metrics as an expvar map, served as JSON
+// by the /debug/vars handler of the expvar package:
+//
+//	{
+//		"requests": {"GET /api/v2/infrastructure/publishers 200": 12},
+//		"errors": {"GET /api/v2/infrastructure/publishers": 1},
+//		"retries": {"POST /api/v2/steering/ipsec/tunnels": 2},
+//		"duration_seconds": {"GET /api/v2/infrastructure/publishers": 3.2},
+//		"in_flight": 0
+//	}
+type Expvar struct {
+	requests *expvar.Map
+	errors   *expvar.Map
+	retries  *expvar.Map
+	duration *expvar.Map
+	inFlight *expvar.Int
+}
+
+// NewExpvar returns an Expvar publishing its metrics under name. Like expvar.Publish, it
+// panics if name is already in use.
+func NewExpvar(name string) *Expvar {
+	e := &Expvar{
+		requests: new(expvar.Map).Init(),
+		errors:   new(expvar.Map).Init(),
+		retries:  new(expvar.Map).Init(),
+		duration: new(expvar.Map).Init(),
+		inFlight: new(expvar.Int),
+	}
+	m := expvar.NewMap(name)
+	m.Set("requests", e.requests)
+	m.Set("errors", e.errors)
+	m.Set("retries", e.retries)
+	m.Set("duration_seconds", e.duration)
+	m.Set("in_flight", e.inFlight)
+	return e
+}
+
+var _ nsgo.Observer = (*Expvar)(nil)
+
+// RequestStart implements nsgo.Observer.
+func (e *Expvar) RequestStart(ctx context.Context, info nsgo.RequestInfo) {
+	e.inFlight.Add(1)
+}
+
+// RequestRetry implements nsgo.Observer.
+func (e *Expvar) RequestRetry(ctx context.Context, info nsgo.RequestInfo) {
+	e.retries.Add(info.Method+" "+info.Route, 1)
+}
+
+// RequestResponse implements nsgo.Observer.
+func (e *Expvar) RequestResponse(ctx context.Context, info nsgo.RequestInfo, status int, duration time.Duration) {
+	e.inFlight.Add(-1)
+	e.requests.Add(info.Method+" "+info.Route+" "+strconv.Itoa(status), 1)
+	e.duration.AddFloat(info.Method+" "+info.Route, duration.Seconds())
+}
+
+// RequestError implements nsgo.Observer.
+func (e *Expvar) RequestError(ctx context.Context, info nsgo.RequestInfo, err error, duration time.Duration) {
+	e.inFlight.Add(-1)
+	e.errors.Add(info.Method+" "+info.Route, 1)
+	e.duration.AddFloat(info.Method+" "+info.Route, duration.Seconds())
+}
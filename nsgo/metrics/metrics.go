@@ -0,0 +1,206 @@
+// Package metrics provides nsgo.Observers collecting per-endpoint request metrics, published
+// either as expvar variables or in the Prometheus text exposition format, without dependencies.
+//
+//	collector := metrics.NewCollector()
+//	nsclient, err := nsgo.New(baseURL, nsgo.WithAPIToken(token), nsgo.WithObserver(collector))
+//	http.Handle("/metrics", collector)
+//
+// Requests are grouped by method and route template (i.e. "/api/v2/steering/apps/private/{id}"),
+// so the number of series does not grow with the number of objects of the tenant.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+)
+
+// Buckets are the upper bounds, in seconds, of the request duration histogram of a Collector.
+var Buckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type endpoint struct {
+	method, route string
+}
+
+type series struct {
+	statuses map[int]uint64
+	errors   uint64
+	retries  uint64
+	buckets  []uint64 // cumulative counts per bound of Buckets
+	count    uint64
+	sum      float64
+}
+
+// Collector is an nsgo.Observer aggregating request counts, errors, retries and durations per
+// endpoint. It is an http.Handler serving them in the Prometheus text exposition format.
+type Collector struct {
+	mu       sync.Mutex
+	series   map[endpoint]*series
+	inFlight int64
+}
+
+// NewCollector returns an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{series: map[endpoint]*series{}}
+}
+
+var _ nsgo.Observer = (*Collector)(nil)
+
+func (c *Collector) get(info nsgo.RequestInfo) *series {
+	key := endpoint{info.Method, info.Route}
+	s, ok := c.series[key]
+	if !ok {
+		s = &series{statuses: map[int]uint64{}, buckets: make([]uint64, len(Buckets))}
+		c.series[key] = s
+	}
+	return s
+}
+
+// RequestStart implements nsgo.Observer.
+func (c *Collector) RequestStart(ctx context.Context, info nsgo.RequestInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight++
+	c.get(info)
+}
+
+// RequestRetry implements nsgo.Observer.
+func (c *Collector) RequestRetry(ctx context.Context, info nsgo.RequestInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.get(info).retries++
+}
+
+// RequestResponse implements nsgo.Observer.
+func (c *Collector) RequestResponse(ctx context.Context, info nsgo.RequestInfo, status int, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight--
+	s := c.get(info)
+	s.statuses[status]++
+	s.observe(duration)
+}
+
+// RequestError implements nsgo.Observer.
+func (c *Collector) RequestError(ctx context.Context, info nsgo.RequestInfo, err error, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight--
+	s := c.get(info)
+	s.errors++
+	s.observe(duration)
+}
+
+func (s *series) observe(d time.Duration) {
+	seconds := d.Seconds()
+	s.count++
+	s.sum += seconds
+	for i, bound := range Buckets {
+		if seconds <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format. They are rendered before
+// anything is sent, so that a failure is reported with a 500 status rather than a truncated page.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := c.WritePrometheus(&buf); err != nil {
+		http.Error(w, "rendering metrics: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	// An error here means the scraper went away, and there is no one left to tell.
+	_, _ = w.Write(buf.Bytes())
+}
+
+// WritePrometheus writes the metrics to w in the Prometheus text exposition format.
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]endpoint, 0, len(c.series))
+	for k := range c.series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].method < keys[j].method
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP nsgo_requests_total Requests that got a response, by status code.\n")
+	b.WriteString("# TYPE nsgo_requests_total counter\n")
+	for _, k := range keys {
+		s := c.series[k]
+		statuses := make([]int, 0, len(s.statuses))
+		for status := range s.statuses {
+			statuses = append(statuses, status)
+		}
+		sort.Ints(statuses)
+		for _, status := range statuses {
+			fmt.Fprintf(&b, "nsgo_requests_total{%s,status=\"%d\"} %d\n", labels(k), status, s.statuses[status])
+		}
+	}
+
+	b.WriteString("# HELP nsgo_request_errors_total Requests that got no response.\n")
+	b.WriteString("# TYPE nsgo_request_errors_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "nsgo_request_errors_total{%s} %d\n", labels(k), c.series[k].errors)
+	}
+
+	b.WriteString("# HELP nsgo_request_retries_total Attempts made after the first one.\n")
+	b.WriteString("# TYPE nsgo_request_retries_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "nsgo_request_retries_total{%s} %d\n", labels(k), c.series[k].retries)
+	}
+
+	b.WriteString("# HELP nsgo_request_duration_seconds Duration of the requests, retries included.\n")
+	b.WriteString("# TYPE nsgo_request_duration_seconds histogram\n")
+	for _, k := range keys {
+		s := c.series[k]
+		for i, bound := range Buckets {
+			fmt.Fprintf(&b, "nsgo_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels(k), formatFloat(bound), s.buckets[i])
+		}
+		fmt.Fprintf(&b, "nsgo_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels(k), s.count)
+		fmt.Fprintf(&b, "nsgo_request_duration_seconds_sum{%s} %s\n", labels(k), formatFloat(s.sum))
+		fmt.Fprintf(&b, "nsgo_request_duration_seconds_count{%s} %d\n", labels(k), s.count)
+	}
+
+	b.WriteString("# HELP nsgo_requests_in_flight Requests waiting for a response.\n")
+	b.WriteString("# TYPE nsgo_requests_in_flight gauge\n")
+	fmt.Fprintf(&b, "nsgo_requests_in_flight %d\n", c.inFlight)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func labels(k endpoint) string {
+	return fmt.Sprintf("method=\"%s\",route=\"%s\"", escape(k.method), escape(k.route))
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,83 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/metrics"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func exercise(t *testing.T, obs nsgo.Observer) {
+	t.Helper()
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client(nsgo.WithObserver(obs), nsgo.WithRetry(nsgo.RetryConfig{RetryMax: 2}))
+	ctx := context.Background()
+
+	id := srv.AddPublisher(nsgotest.Publisher{Name: "pub-1"})
+	for i := 0; i < 2; i++ {
+		if _, err := nsclient.Publishers.Get(ctx, nsgo.PublisherOptions{Id: strconv.Itoa(id)}); err != nil {
+			t.Fatalf("Publishers.Get: %v", err)
+		}
+	}
+	nsclient.Publishers.Get(ctx, nsgo.PublisherOptions{Id: "999"})
+	srv.Fail(nsgotest.Failure{Path: "/api/v2/steering/ipsec/tunnels", Status: http.StatusServiceUnavailable})
+	if _, err := nsclient.IPsec.ListTunnels(ctx); err != nil {
+		t.Fatalf("IPsec.ListTunnels: %v", err)
+	}
+}
+
+func TestCollector(t *testing.T) {
+	collector := metrics.NewCollector()
+	exercise(t, collector)
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, line := range []string{
+		`nsgo_requests_total{method="GET",route="/api/v2/infrastructure/publishers/{id}",status="200"} 2`,
+		`nsgo_requests_total{method="GET",route="/api/v2/infrastructure/publishers/{id}",status="404"} 1`,
+		`nsgo_request_retries_total{method="GET",route="/api/v2/steering/ipsec/tunnels"} 1`,
+		`nsgo_request_duration_seconds_count{method="GET",route="/api/v2/infrastructure/publishers/{id}"} 3`,
+		`nsgo_request_duration_seconds_bucket{method="GET",route="/api/v2/steering/ipsec/tunnels",le="+Inf"} 1`,
+		`nsgo_requests_in_flight 0`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics do not contain %s:\n%s", line, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestExpvar(t *testing.T) {
+	exercise(t, metrics.NewExpvar("nsgo_test"))
+
+	var vars struct {
+		Requests map[string]int     `json:"requests"`
+		Retries  map[string]int     `json:"retries"`
+		Duration map[string]float64 `json:"duration_seconds"`
+		InFlight int                `json:"in_flight"`
+	}
+	if err := json.Unmarshal([]byte(expvar.Get("nsgo_test").String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if vars.Requests["GET /api/v2/infrastructure/publishers/{id} 200"] != 2 || vars.Requests["GET /api/v2/infrastructure/publishers/{id} 404"] != 1 {
+		t.Errorf("requests = %v", vars.Requests)
+	}
+	if vars.Retries["GET /api/v2/steering/ipsec/tunnels"] != 1 || vars.InFlight != 0 {
+		t.Errorf("retries = %v, in flight = %d", vars.Retries, vars.InFlight)
+	}
+	if vars.Duration["GET /api/v2/steering/ipsec/tunnels"] <= 0 {
+		t.Errorf("duration_seconds = %v", vars.Duration)
+	}
+}
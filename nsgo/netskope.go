@@ -2,21 +2,34 @@ package nsgo
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"time"
-
-	"github.com/hashicorp/go-retryablehttp"
 )
 
 //The client struct defines a new HttpClient with the required connection details.
 //BaseURL is a string that represents the Netskope tenant URL. (i.e. "https://example-tenant.goskope.com")
-//apiToken is a string that represents the Netskope API v2 Token.
+//credentials supply the Netskope API v2 Token.
+//Use New to build a Client; the zero value is not usable.
+//
+//The API operations are grouped by area in the Publishers, PrivateApps, IPsec and UpgradeProfiles
+//fields. They are interfaces so that tests can replace them, i.e. with the fakes of the nsgomock package.
 type Client struct {
-	BaseURL    string
-	apiToken   string
-	HttpClient *http.Client
+	BaseURL     string
+	credentials CredentialsProvider
+	HttpClient  *http.Client
+	userAgent   string
+	limiter     *rateLimiter
+	logger      *slog.Logger
+	observer    Observer
+	retry       *RetryPolicy
+	retryBudget *retryBudget
+
+	Publishers      PublishersService
+	PrivateApps     PrivateAppsService
+	IPsec           IPsecService
+	UpgradeProfiles UpgradeProfilesService
 }
 
 //RequestOptions defines a struct to pass options to functions.
@@ -25,6 +38,26 @@ type RequestOptions struct {
 	Id   string `json:"id,omitempty"`
 }
 
+//Bool returns a pointer to v. Optional booleans in request structs are pointers so that
+//the PATCH and PUT methods only send the fields that were set, and can set them to false.
+//
+//	nsclient.UpdatePublisher(nsgo.PublisherOptions{Id: "987", Lbrokerconnect: nsgo.Bool(false)})
+func Bool(v bool) *bool {
+	return &v
+}
+
+//Int returns a pointer to v, i.e. for the optional integers of Profile.
+func Int(v int) *int {
+	return &v
+}
+
+//Duration returns a pointer to v, i.e. for the optional durations of Profile.
+//
+//	nsgo.LoadConfig(nsgo.ConfigSource{Overrides: nsgo.Profile{Timeout: nsgo.Duration(0)}})
+func Duration(v time.Duration) *time.Duration {
+	return &v
+}
+
 //The errorResponse struct defines an error response sent by the API.
 //
 type errorResponse struct {
@@ -41,15 +74,6 @@ type successResponse struct {
 	Message string      `json:"message,omitempty"`
 }
 
-//The ipsecResponse struct defines an successful response sent by the IPSec API Endpoint.
-//
-type ipsecResponse struct {
-	Status  int         `json:"status"`
-	Total   int         `json:"total,omitempty"`
-	Result  interface{} `json:"result,omitempty"`
-	Message string      `json:"message,omitempty"`
-}
-
 type PopFilters struct {
 	Name    string `url:"name,omitempty" validate:"excluded_with=Region Country Lat Long Ip Fields"`
 	Region  string `url:"region,omitempty" validate:"excluded_with=Name Country Lat Long Ip Fields"`
@@ -68,11 +92,14 @@ var defaultRetry = &RetryConfig{
 	RetryWaitMax: 20,
 }
 
+//RetryConfig configures the retries of NewRetryClient and WithRetry, with waits in whole seconds.
+//Retries are capped to 100 per minute for the client, all calls together, as with DefaultRetryPolicy.
+//Use WithRetryPolicy for finer control.
 type RetryConfig struct {
 	RetryMax     int
 	RetryWaitMin int
 	RetryWaitMax int
-	Logger       interface{}
+	Logger       interface{} // A *slog.Logger, retryablehttp.Logger or retryablehttp.LeveledLogger; see WithLogger
 }
 
 type Config struct {
@@ -82,63 +109,65 @@ type Config struct {
 }
 
 //The NewClient function accepts the BaseURL and apiToken and returns a client struct.
+//It is a shorthand for New(BaseURL, WithAPIToken(apiToken)).
 func NewClient(BaseURL, apiToken string) *Client {
-
-	return &Client{
-		BaseURL:  BaseURL,
-		apiToken: apiToken,
-		HttpClient: &http.Client{
-			Timeout: time.Minute,
-		},
-	}
+	c, _ := New(BaseURL, WithAPIToken(apiToken))
+	return c
 }
 
 //The NewRetryClient function accepts the BaseURL and apiToken and returns a retryableclient.
 //Use this in place of NewClient to enable automatic retry logic for rate limiting etc.
+//It is a shorthand for New with WithRetry and no overall timeout.
+//As with WithRetry, creates and registration tokens, which are POST requests, are not retried.
 func NewRetryClient(config Config) *Client {
-
-	retryClient := retryablehttp.NewClient()
-
+	retry := defaultRetry
 	if config.RetryConfig != nil {
-		retryClient.RetryMax = config.RetryConfig.RetryMax
-		retryClient.RetryWaitMin = time.Second * time.Duration(config.RetryConfig.RetryWaitMin)
-		retryClient.RetryWaitMax = time.Second * time.Duration(config.RetryConfig.RetryWaitMax)
-		retryClient.Logger = config.RetryConfig.Logger
-
-	} else {
-		retryClient.RetryMax = defaultRetry.RetryMax
-		retryClient.RetryWaitMin = time.Second * time.Duration(defaultRetry.RetryWaitMin)
-		retryClient.RetryWaitMax = time.Second * time.Duration(defaultRetry.RetryWaitMax)
-		retryClient.Logger = defaultRetry.Logger
+		retry = config.RetryConfig
 	}
 
-	return &Client{
-		BaseURL:    config.BaseURL,
-		apiToken:   config.ApiToken,
-		HttpClient: retryClient.StandardClient(),
-	}
+	c, _ := New(config.BaseURL, WithAPIToken(config.ApiToken), WithTimeout(0), WithRetry(*retry))
+	return c
 }
 
 //The sendRequest function is used to package an API request and send it to the defined Netskope tenant(BaseURL).
 //It is called using the client struct, takes an http.Request as input and returns an interface.
+//The request's context governs the whole exchange; when the client retries, cancelling it also aborts any pending back-off.
 func (c *Client) sendRequest(req *http.Request, v interface{}) error {
-	req.Header.Set("user-Agent", "nsgo-api-client/0.3.0")
+	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.Header.Set("Netskope-Api-Token", c.apiToken)
+	token, err := c.credentials.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Netskope-Api-Token", token)
+	req = withCall(req)
 
+	var start time.Time
+	if c.observer != nil {
+		start = time.Now()
+		c.observer.RequestStart(req.Context(), requestInfo(req))
+	}
 	res, err := c.HttpClient.Do(req)
+	if err == nil && (res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden) {
+		res, err = c.replay(req, res, token)
+	}
+	if c.observer != nil {
+		if err != nil {
+			c.observer.RequestError(req.Context(), requestInfo(req), err, time.Since(start))
+		} else {
+			c.observer.RequestResponse(req.Context(), requestInfo(req), res.StatusCode, time.Since(start))
+		}
+	}
 	if err != nil {
 		return err
 	}
 
 	defer res.Body.Close()
 	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(res.Body)
 		var errRes errorResponse
-		if err = json.NewDecoder(res.Body).Decode(&errRes); err == nil {
-			return errors.New(errRes.Message)
-		}
-
-		return fmt.Errorf("unknown error, status code: %d", res.StatusCode)
+		json.Unmarshal(body, &errRes)
+		return newAPIError(req, res, body, errRes.Message)
 	}
 
 	fullResponse := v
@@ -148,3 +177,33 @@ func (c *Client) sendRequest(req *http.Request, v interface{}) error {
 	}
 	return nil
 }
+
+//The replay function sends req once more with a refreshed token after the tenant rejected token with res.
+//It returns res when the credentials provider has no other token, or req cannot be sent again.
+func (c *Client) replay(req *http.Request, res *http.Response, token string) (*http.Response, error) {
+	ctx := req.Context()
+	if req.GetBody == nil && req.Body != nil && req.Body != http.NoBody {
+		return res, nil
+	}
+	fresh, err := c.credentials.Refresh(ctx, token)
+	if err != nil {
+		c.logger.WarnContext(ctx, "nsgo: cannot refresh API token", "error", err)
+		return res, nil
+	}
+	if fresh == token {
+		return res, nil
+	}
+
+	retry := req.Clone(ctx)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return res, nil
+		}
+	}
+	retry.Header.Set("Netskope-Api-Token", fresh)
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	c.logger.InfoContext(ctx, "nsgo: API token rejected, sending the request again with a refreshed token",
+		"method", req.Method, "path", req.URL.Path, "status", res.StatusCode)
+	return c.HttpClient.Do(retry)
+}
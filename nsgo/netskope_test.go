@@ -0,0 +1,98 @@
+package nsgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestAPIError(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+
+	srv.Fail(nsgotest.Failure{Path: "/api/v2/infrastructure/publishers", Status: http.StatusBadRequest, Message: "bad query"})
+	_, err := srv.Client().GetPublishers()
+	var apiErr *nsgo.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetPublishers: err = %v, want an APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "bad query" || apiErr.Method != "GET" || apiErr.Path != "/api/v2/infrastructure/publishers" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if !errors.Is(err, nsgo.ErrBadRequest) {
+		t.Errorf("errors.Is(%v, ErrBadRequest) = false", err)
+	}
+
+	_, err = nsgo.NewClient(srv.URL, "wrong-token").GetPrivateApps()
+	if !errors.Is(err, nsgo.ErrUnauthorized) {
+		t.Errorf("GetPrivateApps with a wrong token: err = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	retry := nsgo.RetryConfig{RetryMax: 3, RetryWaitMin: 0, RetryWaitMax: 0}
+
+	srv.Fail(nsgotest.Failure{Path: "/api/v2/steering/apps/private", Status: http.StatusServiceUnavailable, Times: 2})
+	if _, err := srv.Client(nsgo.WithRetry(retry)).GetPrivateApps(); err != nil {
+		t.Fatalf("GetPrivateApps with retries: %v", err)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("server received %d requests, want 3", n)
+	}
+
+	srv.Fail(nsgotest.Failure{Path: "/api/v2/steering/apps/private", Status: http.StatusInternalServerError, Times: 10})
+	_, err := srv.Client(nsgo.WithRetry(retry)).GetPrivateApps()
+	if !errors.Is(err, nsgo.ErrServer) {
+		t.Errorf("GetPrivateApps after exhausting retries: err = %v, want ErrServer", err)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client(nsgo.WithRateLimit(nsgo.FamilyInfrastructure, nsgo.RateLimit{Rate: 4, Burst: 8}))
+
+	srv.Fail(nsgotest.Failure{Status: http.StatusTooManyRequests, RetryAfter: 2 * time.Second})
+	_, err := nsclient.GetPublishers()
+	var apiErr *nsgo.APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, nsgo.ErrRateLimited) {
+		t.Fatalf("GetPublishers: err = %v, want ErrRateLimited", err)
+	}
+	if apiErr.RateLimit.RetryAfter != 2*time.Second {
+		t.Errorf("RateLimit.RetryAfter = %v, want 2s", apiErr.RateLimit.RetryAfter)
+	}
+	if budget := nsclient.RateLimitBudget(nsgo.FamilyInfrastructure); budget.BlockedUntil.IsZero() {
+		t.Errorf("RateLimitBudget after a 429 = %+v, want the family blocked", budget)
+	}
+
+	// The steering family is not affected by an infrastructure 429.
+	if _, err := nsclient.GetPrivateApps(); err != nil {
+		t.Errorf("GetPrivateApps: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := nsclient.GetPublishersWithContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetPublishersWithContext while blocked: err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	srv.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := srv.Client().GetIpsecTunnelsWithContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetIpsecTunnelsWithContext: err = %v, want context.DeadlineExceeded", err)
+	}
+}
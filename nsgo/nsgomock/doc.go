@@ -0,0 +1,19 @@
+// Package nsgomock provides fakes of the nsgo service interfaces, for testing code that uses a *nsgo.Client
+// without a tenant.
+//
+// Each fake has a <Method>Func field per method of the interface it implements, which is called
+// by the method, and counts its calls:
+//
+//	publishers := &nsgomock.PublishersService{
+//		GetFunc: func(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error) {
+//			return &nsgo.Publisher{ID: 1, Name: "pub-1", Status: "connected"}, nil
+//		},
+//	}
+//	nsclient.Publishers = publishers
+//	...
+//	if publishers.Calls("Get") != 1 { ... }
+//
+// Calling a method whose Func field is not set panics.
+package nsgomock
+
+//go:generate go run gen.go
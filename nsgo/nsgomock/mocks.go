@@ -0,0 +1,488 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package nsgomock
+
+import (
+	"context"
+	"sync"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+)
+
+// PublishersService is a fake nsgo.PublishersService.
+type PublishersService struct {
+	ListFunc                      func(ctx context.Context) (*nsgo.PublishersList, error)
+	ListWithFilterFunc            func(ctx context.Context, filter string) (*nsgo.PublishersList, error)
+	PagesFunc                     func(opts nsgo.ListOptions) *nsgo.Pager[nsgo.PublisherSummary]
+	GetFunc                       func(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error)
+	CreateFunc                    func(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error)
+	UpdateFunc                    func(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error)
+	ReplaceFunc                   func(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error)
+	DeleteFunc                    func(ctx context.Context, options nsgo.PublisherOptions) error
+	TokenFunc                     func(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.PublisherToken, error)
+	BulkUpgradeFunc               func(ctx context.Context, ids []int) ([]nsgo.PublisherUpgradeOutcome, error)
+	AppsFunc                      func(ctx context.Context, publisherID int) ([]nsgo.PublisherApp, error)
+	ReleasesFunc                  func(ctx context.Context) ([]nsgo.PublisherRelease, error)
+	AlertsConfigurationFunc       func(ctx context.Context) (*nsgo.PublisherAlertsConfiguration, error)
+	UpdateAlertsConfigurationFunc func(ctx context.Context, config nsgo.PublisherAlertsConfiguration) (*nsgo.PublisherAlertsConfiguration, error)
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+var _ nsgo.PublishersService = (*PublishersService)(nil)
+
+// List calls m.ListFunc.
+func (m *PublishersService) List(ctx context.Context) (*nsgo.PublishersList, error) {
+	m.record("List")
+	if m.ListFunc == nil {
+		panic("nsgomock: PublishersService.ListFunc is not set")
+	}
+	return m.ListFunc(ctx)
+}
+
+// ListWithFilter calls m.ListWithFilterFunc.
+func (m *PublishersService) ListWithFilter(ctx context.Context, filter string) (*nsgo.PublishersList, error) {
+	m.record("ListWithFilter")
+	if m.ListWithFilterFunc == nil {
+		panic("nsgomock: PublishersService.ListWithFilterFunc is not set")
+	}
+	return m.ListWithFilterFunc(ctx, filter)
+}
+
+// Pages calls m.PagesFunc.
+func (m *PublishersService) Pages(opts nsgo.ListOptions) *nsgo.Pager[nsgo.PublisherSummary] {
+	m.record("Pages")
+	if m.PagesFunc == nil {
+		panic("nsgomock: PublishersService.PagesFunc is not set")
+	}
+	return m.PagesFunc(opts)
+}
+
+// Get calls m.GetFunc.
+func (m *PublishersService) Get(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error) {
+	m.record("Get")
+	if m.GetFunc == nil {
+		panic("nsgomock: PublishersService.GetFunc is not set")
+	}
+	return m.GetFunc(ctx, options)
+}
+
+// Create calls m.CreateFunc.
+func (m *PublishersService) Create(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error) {
+	m.record("Create")
+	if m.CreateFunc == nil {
+		panic("nsgomock: PublishersService.CreateFunc is not set")
+	}
+	return m.CreateFunc(ctx, options)
+}
+
+// Update calls m.UpdateFunc.
+func (m *PublishersService) Update(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error) {
+	m.record("Update")
+	if m.UpdateFunc == nil {
+		panic("nsgomock: PublishersService.UpdateFunc is not set")
+	}
+	return m.UpdateFunc(ctx, options)
+}
+
+// Replace calls m.ReplaceFunc.
+func (m *PublishersService) Replace(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error) {
+	m.record("Replace")
+	if m.ReplaceFunc == nil {
+		panic("nsgomock: PublishersService.ReplaceFunc is not set")
+	}
+	return m.ReplaceFunc(ctx, options)
+}
+
+// Delete calls m.DeleteFunc.
+func (m *PublishersService) Delete(ctx context.Context, options nsgo.PublisherOptions) error {
+	m.record("Delete")
+	if m.DeleteFunc == nil {
+		panic("nsgomock: PublishersService.DeleteFunc is not set")
+	}
+	return m.DeleteFunc(ctx, options)
+}
+
+// Token calls m.TokenFunc.
+func (m *PublishersService) Token(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.PublisherToken, error) {
+	m.record("Token")
+	if m.TokenFunc == nil {
+		panic("nsgomock: PublishersService.TokenFunc is not set")
+	}
+	return m.TokenFunc(ctx, options)
+}
+
+// BulkUpgrade calls m.BulkUpgradeFunc.
+func (m *PublishersService) BulkUpgrade(ctx context.Context, ids []int) ([]nsgo.PublisherUpgradeOutcome, error) {
+	m.record("BulkUpgrade")
+	if m.BulkUpgradeFunc == nil {
+		panic("nsgomock: PublishersService.BulkUpgradeFunc is not set")
+	}
+	return m.BulkUpgradeFunc(ctx, ids)
+}
+
+// Apps calls m.AppsFunc.
+func (m *PublishersService) Apps(ctx context.Context, publisherID int) ([]nsgo.PublisherApp, error) {
+	m.record("Apps")
+	if m.AppsFunc == nil {
+		panic("nsgomock: PublishersService.AppsFunc is not set")
+	}
+	return m.AppsFunc(ctx, publisherID)
+}
+
+// Releases calls m.ReleasesFunc.
+func (m *PublishersService) Releases(ctx context.Context) ([]nsgo.PublisherRelease, error) {
+	m.record("Releases")
+	if m.ReleasesFunc == nil {
+		panic("nsgomock: PublishersService.ReleasesFunc is not set")
+	}
+	return m.ReleasesFunc(ctx)
+}
+
+// AlertsConfiguration calls m.AlertsConfigurationFunc.
+func (m *PublishersService) AlertsConfiguration(ctx context.Context) (*nsgo.PublisherAlertsConfiguration, error) {
+	m.record("AlertsConfiguration")
+	if m.AlertsConfigurationFunc == nil {
+		panic("nsgomock: PublishersService.AlertsConfigurationFunc is not set")
+	}
+	return m.AlertsConfigurationFunc(ctx)
+}
+
+// UpdateAlertsConfiguration calls m.UpdateAlertsConfigurationFunc.
+func (m *PublishersService) UpdateAlertsConfiguration(ctx context.Context, config nsgo.PublisherAlertsConfiguration) (*nsgo.PublisherAlertsConfiguration, error) {
+	m.record("UpdateAlertsConfiguration")
+	if m.UpdateAlertsConfigurationFunc == nil {
+		panic("nsgomock: PublishersService.UpdateAlertsConfigurationFunc is not set")
+	}
+	return m.UpdateAlertsConfigurationFunc(ctx, config)
+}
+
+// Calls returns the number of calls to the named method.
+func (m *PublishersService) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.calls[method]
+}
+
+func (m *PublishersService) record(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.calls == nil {
+		m.calls = map[string]int{}
+	}
+	m.calls[method]++
+}
+
+// PrivateAppsService is a fake nsgo.PrivateAppsService.
+type PrivateAppsService struct {
+	ListFunc           func(ctx context.Context) (*nsgo.PrivateAppsList, error)
+	ListWithFilterFunc func(ctx context.Context, filter string) (*nsgo.PrivateAppsList, error)
+	PagesFunc          func(opts nsgo.ListOptions) *nsgo.Pager[nsgo.PrivateAppSummary]
+	GetFunc            func(ctx context.Context, options nsgo.PrivateAppOptions) (*nsgo.PrivateApp, error)
+	CreateFunc         func(ctx context.Context, app nsgo.PrivateApp) (*nsgo.PrivateApp, error)
+	UpdateFunc         func(ctx context.Context, options nsgo.PrivateAppOptions, app nsgo.PrivateApp) (*nsgo.PrivateApp, error)
+	ReplaceFunc        func(ctx context.Context, options nsgo.PrivateAppOptions, app nsgo.PrivateApp) (*nsgo.PrivateApp, error)
+	DeleteFunc         func(ctx context.Context, options nsgo.PrivateAppOptions) error
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+var _ nsgo.PrivateAppsService = (*PrivateAppsService)(nil)
+
+// List calls m.ListFunc.
+func (m *PrivateAppsService) List(ctx context.Context) (*nsgo.PrivateAppsList, error) {
+	m.record("List")
+	if m.ListFunc == nil {
+		panic("nsgomock: PrivateAppsService.ListFunc is not set")
+	}
+	return m.ListFunc(ctx)
+}
+
+// ListWithFilter calls m.ListWithFilterFunc.
+func (m *PrivateAppsService) ListWithFilter(ctx context.Context, filter string) (*nsgo.PrivateAppsList, error) {
+	m.record("ListWithFilter")
+	if m.ListWithFilterFunc == nil {
+		panic("nsgomock: PrivateAppsService.ListWithFilterFunc is not set")
+	}
+	return m.ListWithFilterFunc(ctx, filter)
+}
+
+// Pages calls m.PagesFunc.
+func (m *PrivateAppsService) Pages(opts nsgo.ListOptions) *nsgo.Pager[nsgo.PrivateAppSummary] {
+	m.record("Pages")
+	if m.PagesFunc == nil {
+		panic("nsgomock: PrivateAppsService.PagesFunc is not set")
+	}
+	return m.PagesFunc(opts)
+}
+
+// Get calls m.GetFunc.
+func (m *PrivateAppsService) Get(ctx context.Context, options nsgo.PrivateAppOptions) (*nsgo.PrivateApp, error) {
+	m.record("Get")
+	if m.GetFunc == nil {
+		panic("nsgomock: PrivateAppsService.GetFunc is not set")
+	}
+	return m.GetFunc(ctx, options)
+}
+
+// Create calls m.CreateFunc.
+func (m *PrivateAppsService) Create(ctx context.Context, app nsgo.PrivateApp) (*nsgo.PrivateApp, error) {
+	m.record("Create")
+	if m.CreateFunc == nil {
+		panic("nsgomock: PrivateAppsService.CreateFunc is not set")
+	}
+	return m.CreateFunc(ctx, app)
+}
+
+// Update calls m.UpdateFunc.
+func (m *PrivateAppsService) Update(ctx context.Context, options nsgo.PrivateAppOptions, app nsgo.PrivateApp) (*nsgo.PrivateApp, error) {
+	m.record("Update")
+	if m.UpdateFunc == nil {
+		panic("nsgomock: PrivateAppsService.UpdateFunc is not set")
+	}
+	return m.UpdateFunc(ctx, options, app)
+}
+
+// Replace calls m.ReplaceFunc.
+func (m *PrivateAppsService) Replace(ctx context.Context, options nsgo.PrivateAppOptions, app nsgo.PrivateApp) (*nsgo.PrivateApp, error) {
+	m.record("Replace")
+	if m.ReplaceFunc == nil {
+		panic("nsgomock: PrivateAppsService.ReplaceFunc is not set")
+	}
+	return m.ReplaceFunc(ctx, options, app)
+}
+
+// Delete calls m.DeleteFunc.
+func (m *PrivateAppsService) Delete(ctx context.Context, options nsgo.PrivateAppOptions) error {
+	m.record("Delete")
+	if m.DeleteFunc == nil {
+		panic("nsgomock: PrivateAppsService.DeleteFunc is not set")
+	}
+	return m.DeleteFunc(ctx, options)
+}
+
+// Calls returns the number of calls to the named method.
+func (m *PrivateAppsService) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.calls[method]
+}
+
+func (m *PrivateAppsService) record(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.calls == nil {
+		m.calls = map[string]int{}
+	}
+	m.calls[method]++
+}
+
+// UpgradeProfilesService is a fake nsgo.UpgradeProfilesService.
+type UpgradeProfilesService struct {
+	ListFunc   func(ctx context.Context) (*nsgo.PublisherUpgradeProfiles, error)
+	PagesFunc  func(opts nsgo.ListOptions) *nsgo.Pager[nsgo.PublisherUpgradeProfile]
+	GetFunc    func(ctx context.Context, options nsgo.PublisherUpgradeProfileOptions) (*nsgo.PublisherUpgradeProfile, error)
+	CreateFunc func(ctx context.Context, options nsgo.PublisherUpgradeProfileOptions) (*nsgo.PublisherUpgradeProfile, error)
+	UpdateFunc func(ctx context.Context, options nsgo.PublisherUpgradeProfileOptions) (*nsgo.PublisherUpgradeProfile, error)
+	DeleteFunc func(ctx context.Context, options nsgo.PublisherUpgradeProfileOptions) error
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+var _ nsgo.UpgradeProfilesService = (*UpgradeProfilesService)(nil)
+
+// List calls m.ListFunc.
+func (m *UpgradeProfilesService) List(ctx context.Context) (*nsgo.PublisherUpgradeProfiles, error) {
+	m.record("List")
+	if m.ListFunc == nil {
+		panic("nsgomock: UpgradeProfilesService.ListFunc is not set")
+	}
+	return m.ListFunc(ctx)
+}
+
+// Pages calls m.PagesFunc.
+func (m *UpgradeProfilesService) Pages(opts nsgo.ListOptions) *nsgo.Pager[nsgo.PublisherUpgradeProfile] {
+	m.record("Pages")
+	if m.PagesFunc == nil {
+		panic("nsgomock: UpgradeProfilesService.PagesFunc is not set")
+	}
+	return m.PagesFunc(opts)
+}
+
+// Get calls m.GetFunc.
+func (m *UpgradeProfilesService) Get(ctx context.Context, options nsgo.PublisherUpgradeProfileOptions) (*nsgo.PublisherUpgradeProfile, error) {
+	m.record("Get")
+	if m.GetFunc == nil {
+		panic("nsgomock: UpgradeProfilesService.GetFunc is not set")
+	}
+	return m.GetFunc(ctx, options)
+}
+
+// Create calls m.CreateFunc.
+func (m *UpgradeProfilesService) Create(ctx context.Context, options nsgo.PublisherUpgradeProfileOptions) (*nsgo.PublisherUpgradeProfile, error) {
+	m.record("Create")
+	if m.CreateFunc == nil {
+		panic("nsgomock: UpgradeProfilesService.CreateFunc is not set")
+	}
+	return m.CreateFunc(ctx, options)
+}
+
+// Update calls m.UpdateFunc.
+func (m *UpgradeProfilesService) Update(ctx context.Context, options nsgo.PublisherUpgradeProfileOptions) (*nsgo.PublisherUpgradeProfile, error) {
+	m.record("Update")
+	if m.UpdateFunc == nil {
+		panic("nsgomock: UpgradeProfilesService.UpdateFunc is not set")
+	}
+	return m.UpdateFunc(ctx, options)
+}
+
+// Delete calls m.DeleteFunc.
+func (m *UpgradeProfilesService) Delete(ctx context.Context, options nsgo.PublisherUpgradeProfileOptions) error {
+	m.record("Delete")
+	if m.DeleteFunc == nil {
+		panic("nsgomock: UpgradeProfilesService.DeleteFunc is not set")
+	}
+	return m.DeleteFunc(ctx, options)
+}
+
+// Calls returns the number of calls to the named method.
+func (m *UpgradeProfilesService) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.calls[method]
+}
+
+func (m *UpgradeProfilesService) record(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.calls == nil {
+		m.calls = map[string]int{}
+	}
+	m.calls[method]++
+}
+
+// IPsecService is a fake nsgo.IPsecService.
+type IPsecService struct {
+	ListPopsFunc            func(ctx context.Context) (*nsgo.IpsecPops, error)
+	ListPopsWithFiltersFunc func(ctx context.Context, filters nsgo.PopFilters) (*nsgo.IpsecPops, error)
+	GetPopFunc              func(ctx context.Context, options nsgo.RequestOptions) (*nsgo.IpsecPop, error)
+	ListTunnelsFunc         func(ctx context.Context) (*nsgo.IpsecTunnels, error)
+	TunnelPagesFunc         func(opts nsgo.ListOptions) *nsgo.Pager[nsgo.IpsecTunnel]
+	GetTunnelFunc           func(ctx context.Context, options nsgo.RequestOptions) (*nsgo.IpsecTunnel, error)
+	CreateTunnelFunc        func(ctx context.Context, tunnel nsgo.NewIpsecTunnel) (*nsgo.IpsecTunnel, error)
+	UpdateTunnelFunc        func(ctx context.Context, options nsgo.RequestOptions, tunnel nsgo.NewIpsecTunnel) (*nsgo.IpsecTunnel, error)
+	DeleteTunnelFunc        func(ctx context.Context, options nsgo.RequestOptions) (*nsgo.IpsecTunnel, error)
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+var _ nsgo.IPsecService = (*IPsecService)(nil)
+
+// ListPops calls m.ListPopsFunc.
+func (m *IPsecService) ListPops(ctx context.Context) (*nsgo.IpsecPops, error) {
+	m.record("ListPops")
+	if m.ListPopsFunc == nil {
+		panic("nsgomock: IPsecService.ListPopsFunc is not set")
+	}
+	return m.ListPopsFunc(ctx)
+}
+
+// ListPopsWithFilters calls m.ListPopsWithFiltersFunc.
+func (m *IPsecService) ListPopsWithFilters(ctx context.Context, filters nsgo.PopFilters) (*nsgo.IpsecPops, error) {
+	m.record("ListPopsWithFilters")
+	if m.ListPopsWithFiltersFunc == nil {
+		panic("nsgomock: IPsecService.ListPopsWithFiltersFunc is not set")
+	}
+	return m.ListPopsWithFiltersFunc(ctx, filters)
+}
+
+// GetPop calls m.GetPopFunc.
+func (m *IPsecService) GetPop(ctx context.Context, options nsgo.RequestOptions) (*nsgo.IpsecPop, error) {
+	m.record("GetPop")
+	if m.GetPopFunc == nil {
+		panic("nsgomock: IPsecService.GetPopFunc is not set")
+	}
+	return m.GetPopFunc(ctx, options)
+}
+
+// ListTunnels calls m.ListTunnelsFunc.
+func (m *IPsecService) ListTunnels(ctx context.Context) (*nsgo.IpsecTunnels, error) {
+	m.record("ListTunnels")
+	if m.ListTunnelsFunc == nil {
+		panic("nsgomock: IPsecService.ListTunnelsFunc is not set")
+	}
+	return m.ListTunnelsFunc(ctx)
+}
+
+// TunnelPages calls m.TunnelPagesFunc.
+func (m *IPsecService) TunnelPages(opts nsgo.ListOptions) *nsgo.Pager[nsgo.IpsecTunnel] {
+	m.record("TunnelPages")
+	if m.TunnelPagesFunc == nil {
+		panic("nsgomock: IPsecService.TunnelPagesFunc is not set")
+	}
+	return m.TunnelPagesFunc(opts)
+}
+
+// GetTunnel calls m.GetTunnelFunc.
+func (m *IPsecService) GetTunnel(ctx context.Context, options nsgo.RequestOptions) (*nsgo.IpsecTunnel, error) {
+	m.record("GetTunnel")
+	if m.GetTunnelFunc == nil {
+		panic("nsgomock: IPsecService.GetTunnelFunc is not set")
+	}
+	return m.GetTunnelFunc(ctx, options)
+}
+
+// CreateTunnel calls m.CreateTunnelFunc.
+func (m *IPsecService) CreateTunnel(ctx context.Context, tunnel nsgo.NewIpsecTunnel) (*nsgo.IpsecTunnel, error) {
+	m.record("CreateTunnel")
+	if m.CreateTunnelFunc == nil {
+		panic("nsgomock: IPsecService.CreateTunnelFunc is not set")
+	}
+	return m.CreateTunnelFunc(ctx, tunnel)
+}
+
+// UpdateTunnel calls m.UpdateTunnelFunc.
+func (m *IPsecService) UpdateTunnel(ctx context.Context, options nsgo.RequestOptions, tunnel nsgo.NewIpsecTunnel) (*nsgo.IpsecTunnel, error) {
+	m.record("UpdateTunnel")
+	if m.UpdateTunnelFunc == nil {
+		panic("nsgomock: IPsecService.UpdateTunnelFunc is not set")
+	}
+	return m.UpdateTunnelFunc(ctx, options, tunnel)
+}
+
+// DeleteTunnel calls m.DeleteTunnelFunc.
+func (m *IPsecService) DeleteTunnel(ctx context.Context, options nsgo.RequestOptions) (*nsgo.IpsecTunnel, error) {
+	m.record("DeleteTunnel")
+	if m.DeleteTunnelFunc == nil {
+		panic("nsgomock: IPsecService.DeleteTunnelFunc is not set")
+	}
+	return m.DeleteTunnelFunc(ctx, options)
+}
+
+// Calls returns the number of calls to the named method.
+func (m *IPsecService) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.calls[method]
+}
+
+func (m *IPsecService) record(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.calls == nil {
+		m.calls = map[string]int{}
+	}
+	m.calls[method]++
+}
@@ -0,0 +1,53 @@
+package nsgomock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgomock"
+)
+
+func TestPublishersService(t *testing.T) {
+	nsclient, err := nsgo.New("https://example-tenant.goskope.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	publishers := &nsgomock.PublishersService{
+		GetFunc: func(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error) {
+			if options.Id != "1" {
+				return nil, nsgo.ErrNotFound
+			}
+			return &nsgo.Publisher{ID: 1, Name: "pub-1"}, nil
+		},
+		PagesFunc: nsgomock.Pager([]nsgo.PublisherSummary{{PublisherName: "pub-1"}, {PublisherName: "pub-2"}, {PublisherName: "pub-3"}}),
+	}
+	nsclient.Publishers = publishers
+
+	pub, err := nsclient.Publishers.Get(context.Background(), nsgo.PublisherOptions{Id: "1"})
+	if err != nil || pub.Name != "pub-1" {
+		t.Errorf("Get = %+v, %v", pub, err)
+	}
+	// The deprecated methods forward to the services.
+	if _, err := nsclient.GetPublisherId(nsgo.PublisherOptions{Id: "2"}); !errors.Is(err, nsgo.ErrNotFound) {
+		t.Errorf("GetPublisherId: err = %v, want ErrNotFound", err)
+	}
+	if got := publishers.Calls("Get"); got != 2 {
+		t.Errorf("Calls(Get) = %d, want 2", got)
+	}
+
+	all, err := nsclient.Publishers.Pages(nsgo.ListOptions{Limit: 2}).All(context.Background())
+	if err != nil || len(all) != 3 {
+		t.Errorf("Pages.All = %v, %v; want 3 publishers", all, err)
+	}
+}
+
+func TestUnsetFuncPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("calling a method without its Func did not panic")
+		}
+	}()
+	(&nsgomock.IPsecService{}).ListPops(context.Background())
+}
@@ -0,0 +1,25 @@
+package nsgomock
+
+import (
+	"context"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+)
+
+// Pager returns a Pager over items, for use in the PagesFunc fields of the fakes.
+func Pager[T any](items []T) func(opts nsgo.ListOptions) *nsgo.Pager[T] {
+	return func(opts nsgo.ListOptions) *nsgo.Pager[T] {
+		return nsgo.NewPager(opts, func(ctx context.Context, opts nsgo.ListOptions) ([]T, int, error) {
+			page := items
+			if opts.Offset < len(page) {
+				page = page[opts.Offset:]
+			} else {
+				page = nil
+			}
+			if opts.Limit < len(page) {
+				page = page[:opts.Limit]
+			}
+			return page, len(items), nil
+		})
+	}
+}
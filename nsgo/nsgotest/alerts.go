@@ -0,0 +1,65 @@
+package nsgotest
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AlertsConfiguration is the publisher alerts configuration stored by the Server.
+type AlertsConfiguration struct {
+	AdminUsers    []string
+	EventTypes    []string
+	SelectedUsers string
+}
+
+// alertEvents are the event types accepted by the tenant.
+var alertEvents = map[string]bool{
+	"UPGRADE_WILL_START": true,
+	"UPGRADE_STARTED":    true,
+	"UPGRADE_SUCCEEDED":  true,
+	"UPGRADE_FAILED":     true,
+	"CONNECTION_FAILED":  true,
+}
+
+// AlertsConfiguration returns the stored publisher alerts configuration.
+func (s *Server) AlertsConfiguration() AlertsConfiguration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.alerts
+}
+
+func (s *Server) alertsView() map[string]interface{} {
+	return map[string]interface{}{
+		"adminUsers":    orEmpty(s.alerts.AdminUsers),
+		"eventTypes":    orEmpty(s.alerts.EventTypes),
+		"selectedUsers": s.alerts.SelectedUsers,
+	}
+}
+
+func (s *Server) serveAlerts(w http.ResponseWriter, r *http.Request, body []byte) {
+	switch r.Method {
+	case http.MethodGet:
+		writeSuccess(w, s.alertsView(), -1)
+	case http.MethodPut:
+		var req struct {
+			AdminUsers    []string `json:"adminUsers"`
+			EventTypes    []string `json:"eventTypes"`
+			SelectedUsers string   `json:"selectedUsers"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, false, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, e := range req.EventTypes {
+			if !alertEvents[e] {
+				writeError(w, false, http.StatusBadRequest, "unknown event type "+e)
+				return
+			}
+		}
+		s.alerts = AlertsConfiguration{AdminUsers: req.AdminUsers, EventTypes: req.EventTypes, SelectedUsers: req.SelectedUsers}
+		writeSuccess(w, s.alertsView(), -1)
+	default:
+		writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,217 @@
+package nsgotest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// IpsecPop is an IPSec PoP served by the Server.
+type IpsecPop struct {
+	ID       string
+	Name     string
+	Gateway  string
+	Probeip  string
+	Region   string
+	Country  string
+	Location string
+}
+
+// IpsecTunnel is an IPSec tunnel stored by the Server.
+type IpsecTunnel struct {
+	ID            int
+	Site          string
+	Enabled       bool
+	Pops          []string
+	Encryption    string
+	Srcidentity   string
+	Srcipidentity string
+	Psk           string
+	Notes         string
+	Sourcetype    string
+	Bandwidth     int
+	Status        string
+}
+
+// SetIpsecPops replaces the PoPs served by the Server.
+func (s *Server) SetIpsecPops(pops []IpsecPop) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pops = append([]IpsecPop(nil), pops...)
+}
+
+// AddIpsecTunnel stores t, assigning it an ID when it has none, and returns its ID.
+func (s *Server) AddIpsecTunnel(t IpsecTunnel) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.ID == 0 {
+		t.ID = s.newID()
+	}
+	s.tunnels[t.ID] = &t
+	return t.ID
+}
+
+// IpsecTunnel returns the stored IPSec tunnel with the given ID.
+func (s *Server) IpsecTunnel(id int) (IpsecTunnel, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tunnels[id]
+	if !ok {
+		return IpsecTunnel{}, false
+	}
+	return *t, true
+}
+
+func (p IpsecPop) view() map[string]interface{} {
+	phase := map[string]interface{}{
+		"dhgroup":        "14",
+		"encryptionalgo": "aes256cbc",
+		"integrityalgo":  "sha256",
+		"salifetime":     "3600",
+	}
+	phase1 := map[string]interface{}{"ikeversion": "2"}
+	for k, v := range phase {
+		phase1[k] = v
+	}
+	return map[string]interface{}{
+		"id":       p.ID,
+		"name":     p.Name,
+		"gateway":  p.Gateway,
+		"probeip":  p.Probeip,
+		"region":   p.Region,
+		"location": p.Location,
+		"options":  map[string]interface{}{"phase1": phase1, "phase2": phase},
+	}
+}
+
+func (s *Server) tunnelView(t *IpsecTunnel) map[string]interface{} {
+	pops := []map[string]interface{}{}
+	for i, name := range t.Pops {
+		pop := map[string]interface{}{"name": name, "primary": i == 0}
+		for _, p := range s.pops {
+			if p.Name == name {
+				pop["gateway"] = p.Gateway
+				pop["probeip"] = p.Probeip
+			}
+		}
+		pops = append(pops, pop)
+	}
+	status := t.Status
+	if status == "" {
+		status = "down"
+	}
+	return map[string]interface{}{
+		"id":            t.ID,
+		"site":          t.Site,
+		"enabled":       t.Enabled,
+		"pops":          pops,
+		"status":        map[string]interface{}{"status": status, "since": "", "throughput": ""},
+		"template":      "",
+		"sourcetype":    t.Sourcetype,
+		"notes":         t.Notes,
+		"encryption":    t.Encryption,
+		"srcidentity":   t.Srcidentity,
+		"srcipidentity": t.Srcipidentity,
+	}
+}
+
+// applyTunnel updates t with the fields sent in body.
+func applyTunnel(t *IpsecTunnel, body []byte) error {
+	m, err := fields(body)
+	if err != nil {
+		return err
+	}
+	set(m, "site", &t.Site)
+	set(m, "encryption", &t.Encryption)
+	set(m, "srcidentity", &t.Srcidentity)
+	set(m, "srcipidentity", &t.Srcipidentity)
+	set(m, "psk", &t.Psk)
+	set(m, "notes", &t.Notes)
+	set(m, "sourcetype", &t.Sourcetype)
+	set(m, "bandwidth", &t.Bandwidth)
+	set(m, "enable", &t.Enabled)
+	set(m, "pops", &t.Pops)
+	return nil
+}
+
+func (s *Server) servePops(w http.ResponseWriter, r *http.Request, rest []string) {
+	if r.Method != http.MethodGet {
+		writeError(w, true, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	q := r.URL.Query()
+	if len(rest) == 1 {
+		for _, p := range s.pops {
+			if p.ID == rest[0] {
+				// Single PoPs are returned as a list of one, like the tenant does.
+				writeIpsec(w, http.StatusOK, []map[string]interface{}{p.view()}, 1)
+				return
+			}
+		}
+		writeError(w, true, http.StatusNotFound, notFound("pop", rest[0]))
+		return
+	}
+
+	var items []map[string]interface{}
+	for _, p := range s.pops {
+		switch {
+		case q.Get("name") != "" && !strings.EqualFold(q.Get("name"), p.Name):
+		case q.Get("region") != "" && !strings.EqualFold(q.Get("region"), p.Region):
+		case q.Get("country") != "" && !strings.EqualFold(q.Get("country"), p.Country):
+		default:
+			items = append(items, p.view())
+		}
+	}
+	writeIpsec(w, http.StatusOK, orEmpty(page(q, items, s.pageSize)), len(items))
+}
+
+func (s *Server) serveTunnels(w http.ResponseWriter, r *http.Request, rest []string, body []byte) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var items []map[string]interface{}
+			for _, id := range sortedIDs(s.tunnels) {
+				items = append(items, s.tunnelView(s.tunnels[id]))
+			}
+			writeIpsec(w, http.StatusOK, orEmpty(page(r.URL.Query(), items, s.pageSize)), len(items))
+		case http.MethodPost:
+			t := IpsecTunnel{}
+			if err := applyTunnel(&t, body); err != nil || t.Site == "" {
+				writeError(w, true, http.StatusBadRequest, "a site is required")
+				return
+			}
+			t.ID = s.newID()
+			s.tunnels[t.ID] = &t
+			writeIpsec(w, http.StatusCreated, s.tunnelView(&t), -1)
+		default:
+			writeError(w, true, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	id, _ := strconv.Atoi(rest[0])
+	t, ok := s.tunnels[id]
+	if !ok || len(rest) != 1 {
+		writeError(w, true, http.StatusNotFound, notFound("tunnel", rest[0]))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeIpsec(w, http.StatusOK, []map[string]interface{}{s.tunnelView(t)}, 1)
+	case http.MethodPatch:
+		if err := applyTunnel(t, body); err != nil {
+			writeError(w, true, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeIpsec(w, http.StatusOK, s.tunnelView(t), -1)
+	case http.MethodDelete:
+		delete(s.tunnels, id)
+		writeIpsec(w, http.StatusOK, map[string]interface{}{}, -1)
+	default:
+		writeError(w, true, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,254 @@
+package nsgotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrivateApp is a private app stored by the Server.
+type PrivateApp struct {
+	ID                   int
+	Name                 string
+	Host                 string
+	Protocols            []Protocol
+	PublisherIDs         []int
+	Tags                 []string
+	ClientlessAccess     bool
+	UsePublisherDNS      bool
+	TrustSelfSignedCerts bool
+	Reachable            bool
+}
+
+// Protocol is a port and transport served by a PrivateApp.
+type Protocol struct {
+	Type string
+	Port string
+}
+
+// AddPrivateApp stores app, assigning it an ID when it has none, and returns its ID.
+func (s *Server) AddPrivateApp(app PrivateApp) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if app.ID == 0 {
+		app.ID = s.newID()
+	}
+	s.apps[app.ID] = &app
+	return app.ID
+}
+
+// PrivateApp returns the stored private app with the given ID.
+func (s *Server) PrivateApp(id int) (PrivateApp, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app, ok := s.apps[id]
+	if !ok {
+		return PrivateApp{}, false
+	}
+	return *app, true
+}
+
+func (s *Server) appPublishers(app *PrivateApp) []map[string]interface{} {
+	pubs := []map[string]interface{}{}
+	for _, id := range app.PublisherIDs {
+		name := ""
+		if p, ok := s.publisher[id]; ok {
+			name = p.Name
+		}
+		pubs = append(pubs, map[string]interface{}{"publisher_id": id, "publisher_name": name})
+	}
+	return pubs
+}
+
+func (app *PrivateApp) reachability() map[string]interface{} {
+	if app.Reachable {
+		return map[string]interface{}{"reachable": true}
+	}
+	return map[string]interface{}{"reachable": false, "error_code": 1001, "error_string": "host unreachable"}
+}
+
+func (app *PrivateApp) protocols(key string) []map[string]interface{} {
+	protocols := []map[string]interface{}{}
+	for i, p := range app.Protocols {
+		view := map[string]interface{}{key: p.Type, "port": p.Port}
+		if key == "transport" {
+			now := time.Now().UTC().Format("2006-01-02 15:04:05")
+			view["id"] = i + 1
+			view["service_id"] = app.ID
+			view["created_at"] = now
+			view["updated_at"] = now
+		}
+		protocols = append(protocols, view)
+	}
+	return protocols
+}
+
+func (s *Server) appListView(app *PrivateApp) map[string]interface{} {
+	assignments := []map[string]interface{}{}
+	for i, id := range app.PublisherIDs {
+		assignments = append(assignments, map[string]interface{}{
+			"primary":      strconv.FormatBool(i == 0),
+			"publisher_id": id,
+			"reachability": app.reachability(),
+			"service_id":   app.ID,
+		})
+	}
+	return map[string]interface{}{
+		"app_id":                        app.ID,
+		"app_name":                      app.Name,
+		"clientless_access":             app.ClientlessAccess,
+		"host":                          app.Host,
+		"private_app_protocol":          "",
+		"protocols":                     app.protocols("transport"),
+		"reachability":                  app.reachability(),
+		"service_publisher_assignments": assignments,
+		"trust_self_signed_certs":       app.TrustSelfSignedCerts,
+		"use_publisher_dns":             app.UsePublisherDNS,
+	}
+}
+
+func (s *Server) appView(app *PrivateApp) map[string]interface{} {
+	tags := []map[string]interface{}{}
+	for _, t := range app.Tags {
+		tags = append(tags, map[string]interface{}{"tag_name": t})
+	}
+	return map[string]interface{}{
+		"id":                      app.ID,
+		"app_name":                app.Name,
+		"host":                    app.Host,
+		"protocols":               app.protocols("type"),
+		"publishers":              s.appPublishers(app),
+		"tags":                    tags,
+		"clientless_access":       app.ClientlessAccess,
+		"use_publisher_dns":       app.UsePublisherDNS,
+		"trust_self_signed_certs": app.TrustSelfSignedCerts,
+		"reachability":            app.reachability(),
+	}
+}
+
+// applyPrivateApp updates app with the fields sent in body.
+func applyPrivateApp(app *PrivateApp, body []byte) error {
+	m, err := fields(body)
+	if err != nil {
+		return err
+	}
+	set(m, "app_name", &app.Name)
+	set(m, "host", &app.Host)
+	set(m, "clientless_access", &app.ClientlessAccess)
+	set(m, "use_publisher_dns", &app.UsePublisherDNS)
+	set(m, "trust_self_signed_certs", &app.TrustSelfSignedCerts)
+	if raw, ok := m["protocols"]; ok {
+		var protocols []struct {
+			Type string `json:"type"`
+			Port string `json:"port"`
+		}
+		if err := json.Unmarshal(raw, &protocols); err != nil {
+			return err
+		}
+		app.Protocols = nil
+		for _, p := range protocols {
+			app.Protocols = append(app.Protocols, Protocol{Type: p.Type, Port: p.Port})
+		}
+	}
+	if raw, ok := m["publishers"]; ok {
+		// Publisher IDs are accepted as numbers or strings.
+		var pubs []struct {
+			PublisherID json.RawMessage `json:"publisher_id"`
+		}
+		if err := json.Unmarshal(raw, &pubs); err != nil {
+			return err
+		}
+		app.PublisherIDs = nil
+		for _, p := range pubs {
+			if id, err := strconv.Atoi(strings.Trim(string(p.PublisherID), `"`)); err == nil {
+				app.PublisherIDs = append(app.PublisherIDs, id)
+			}
+		}
+	}
+	if raw, ok := m["tags"]; ok {
+		var tags []struct {
+			TagName string `json:"tag_name"`
+		}
+		if err := json.Unmarshal(raw, &tags); err != nil {
+			return err
+		}
+		app.Tags = nil
+		for _, t := range tags {
+			app.Tags = append(app.Tags, t.TagName)
+		}
+	}
+	return nil
+}
+
+func (s *Server) servePrivateApps(w http.ResponseWriter, r *http.Request, rest []string, body []byte) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var items []map[string]interface{}
+			for _, id := range sortedIDs(s.apps) {
+				app := s.apps[id]
+				values := map[string]string{"app_name": app.Name, "host": app.Host, "app_id": strconv.Itoa(app.ID)}
+				if matchQuery(r.URL.Query().Get("query"), app.Name, values) {
+					items = append(items, s.appListView(app))
+				}
+			}
+			total := len(items)
+			writeSuccess(w, map[string]interface{}{"private_apps": orEmpty(page(r.URL.Query(), items, s.pageSize))}, total)
+		case http.MethodPost:
+			app := PrivateApp{}
+			if err := applyPrivateApp(&app, body); err != nil || app.Name == "" || app.Host == "" {
+				writeError(w, false, http.StatusBadRequest, "app_name and host are required")
+				return
+			}
+			for _, existing := range s.apps {
+				if strings.EqualFold(existing.Name, app.Name) {
+					writeError(w, false, http.StatusConflict, "private app name already exists")
+					return
+				}
+			}
+			app.ID = s.newID()
+			s.apps[app.ID] = &app
+			writeSuccess(w, s.appView(&app), -1)
+		default:
+			writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	id, _ := parseID(rest[0])
+	app, ok := s.apps[id]
+	if !ok || len(rest) != 1 {
+		writeError(w, false, http.StatusNotFound, notFound("private app", rest[0]))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		view := s.appListView(app)
+		view["publishers"] = s.appPublishers(app)
+		writeSuccess(w, view, -1)
+	case http.MethodPatch:
+		if err := applyPrivateApp(app, body); err != nil {
+			writeError(w, false, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeSuccess(w, s.appView(app), -1)
+	case http.MethodPut:
+		replaced := PrivateApp{ID: app.ID, Reachable: app.Reachable}
+		if err := applyPrivateApp(&replaced, body); err != nil {
+			writeError(w, false, http.StatusBadRequest, err.Error())
+			return
+		}
+		*app = replaced
+		writeSuccess(w, s.appView(app), -1)
+	case http.MethodDelete:
+		delete(s.apps, id)
+		writeSuccess(w, nil, -1)
+	default:
+		writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
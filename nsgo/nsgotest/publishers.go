@@ -0,0 +1,298 @@
+package nsgotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Publisher is a publisher stored by the Server.
+type Publisher struct {
+	ID               int
+	Name             string
+	Lbrokerconnect   bool
+	Registered       bool
+	Status           string
+	StitcherID       int
+	UpgradeProfileID int
+	Tags             []string
+	Version          string
+	IPAddress        string
+	// UpgradeRequest is set by bulk upgrade requests. Upstat, UpgradeFailedReason and
+	// UpgradeFailedVersion report the progress of the upgrade, and are left to tests to set.
+	UpgradeRequest       bool
+	Upstat               string
+	UpgradeFailedReason  string
+	UpgradeFailedVersion string
+}
+
+// AddPublisher stores p, assigning it an ID when it has none, and returns its ID.
+func (s *Server) AddPublisher(p Publisher) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addPublisher(p)
+}
+
+func (s *Server) addPublisher(p Publisher) int {
+	if p.ID == 0 {
+		p.ID = s.newID()
+	}
+	if p.Status == "" {
+		p.Status = "not registered"
+	}
+	s.publisher[p.ID] = &p
+	return p.ID
+}
+
+// Publisher returns the stored publisher with the given ID.
+func (s *Server) Publisher(id int) (Publisher, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.publisher[id]
+	if !ok {
+		return Publisher{}, false
+	}
+	return *p, true
+}
+
+// UpdatePublisher applies fn to the stored publisher with the given ID, i.e. to simulate
+// a publisher registering. It reports whether the publisher exists.
+func (s *Server) UpdatePublisher(id int, fn func(*Publisher)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.publisher[id]
+	if ok {
+		fn(p)
+	}
+	return ok
+}
+
+func (p *Publisher) listView() map[string]interface{} {
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	failed := map[string]interface{}{}
+	if p.UpgradeFailedReason != "" {
+		failed = map[string]interface{}{"detail": p.UpgradeFailedReason, "error_code": 1, "timestamp": 0, "version": p.UpgradeFailedVersion}
+	}
+	return map[string]interface{}{
+		"assessment": map[string]interface{}{
+			"eee_support": "false",
+			"hdd_free":    "30G",
+			"hdd_total":   "40G",
+			"ip_address":  p.IPAddress,
+			"latency":     10,
+			"version":     p.Version,
+		},
+		"common_name":                            fmt.Sprintf("publisher-%d", p.ID),
+		"lbrokerconnect":                         p.Lbrokerconnect,
+		"publisher_id":                           p.ID,
+		"publisher_name":                         p.Name,
+		"publisher_upgrade_profiles_external_id": p.UpgradeProfileID,
+		"registered":                             p.Registered,
+		"status":                                 p.Status,
+		"stitcher_id":                            p.StitcherID,
+		"tags":                                   tags,
+		"upgrade_failed_reason":                  failed,
+		"upgrade_request":                        p.UpgradeRequest,
+		"upgrade_status":                         map[string]interface{}{"upstat": p.Upstat},
+	}
+}
+
+func (p *Publisher) view() map[string]interface{} {
+	return map[string]interface{}{
+		"assessment": map[string]interface{}{
+			"eee_support": false,
+			"hdd_free":    "30G",
+			"hdd_total":   "40G",
+			"ip_address":  p.IPAddress,
+			"version":     p.Version,
+		},
+		"common_name": fmt.Sprintf("publisher-%d", p.ID),
+		"id":          p.ID,
+		"name":        p.Name,
+		"registered":  p.Registered,
+		"status":      p.Status,
+		"stitcher_id": p.StitcherID,
+	}
+}
+
+// applyPublisher updates p with the fields sent in body.
+func applyPublisher(p *Publisher, body []byte) error {
+	m, err := fields(body)
+	if err != nil {
+		return err
+	}
+	set(m, "name", &p.Name)
+	set(m, "lbrokerconnect", &p.Lbrokerconnect)
+	set(m, "publisher_upgrade_profiles_id", &p.UpgradeProfileID)
+	if _, ok := m["tags"]; ok {
+		var tags []struct {
+			TagName string `json:"tag_name"`
+		}
+		set(m, "tags", &tags)
+		p.Tags = nil
+		for _, t := range tags {
+			p.Tags = append(p.Tags, t.TagName)
+		}
+	}
+	return nil
+}
+
+func (s *Server) servePublishers(w http.ResponseWriter, r *http.Request, rest []string, body []byte) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var items []map[string]interface{}
+			for _, id := range sortedIDs(s.publisher) {
+				p := s.publisher[id]
+				values := map[string]string{"publisher_name": p.Name, "status": p.Status, "publisher_id": strconv.Itoa(p.ID)}
+				if matchQuery(r.URL.Query().Get("query"), p.Name, values) {
+					items = append(items, p.listView())
+				}
+			}
+			total := len(items)
+			writeSuccess(w, map[string]interface{}{"publishers": orEmpty(page(r.URL.Query(), items, s.pageSize))}, total)
+		case http.MethodPost:
+			p := Publisher{}
+			if err := applyPublisher(&p, body); err != nil || p.Name == "" {
+				writeError(w, false, http.StatusBadRequest, "a publisher name is required")
+				return
+			}
+			for _, existing := range s.publisher {
+				if existing.Name == p.Name {
+					writeError(w, false, http.StatusConflict, "publisher name already exists")
+					return
+				}
+			}
+			id := s.addPublisher(p)
+			writeSuccess(w, s.publisher[id].view(), -1)
+		default:
+			writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if rest[0] == "bulk" && len(rest) == 1 {
+		s.serveBulkPublishers(w, r, body)
+		return
+	}
+	if rest[0] == "releases" && len(rest) == 1 {
+		s.serveReleases(w, r)
+		return
+	}
+	if rest[0] == "alertsconfiguration" && len(rest) == 1 {
+		s.serveAlerts(w, r, body)
+		return
+	}
+
+	id, _ := parseID(rest[0])
+	p, ok := s.publisher[id]
+	if !ok {
+		writeError(w, false, http.StatusNotFound, notFound("publisher", rest[0]))
+		return
+	}
+
+	if len(rest) == 2 && rest[1] == "apps" && r.Method == http.MethodGet {
+		writeSuccess(w, s.publisherApps(p.ID), -1)
+		return
+	}
+	if len(rest) == 2 && rest[1] == "registration_token" && r.Method == http.MethodPost {
+		writeSuccess(w, map[string]interface{}{"token": fmt.Sprintf("token-%d-%d", p.ID, s.newID())}, -1)
+		return
+	}
+	if len(rest) != 1 {
+		writeError(w, false, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeSuccess(w, p.view(), -1)
+	case http.MethodPatch:
+		if err := applyPublisher(p, body); err != nil {
+			writeError(w, false, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeSuccess(w, p.view(), -1)
+	case http.MethodPut:
+		replaced := Publisher{ID: p.ID, Registered: p.Registered, Status: p.Status, StitcherID: p.StitcherID, Version: p.Version, IPAddress: p.IPAddress}
+		if err := applyPublisher(&replaced, body); err != nil {
+			writeError(w, false, http.StatusBadRequest, err.Error())
+			return
+		}
+		*p = replaced
+		writeSuccess(w, p.view(), -1)
+	case http.MethodDelete:
+		delete(s.publisher, id)
+		writeSuccess(w, nil, -1)
+	default:
+		writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// publisherApps returns the views of the private apps served by the publisher with the given ID.
+func (s *Server) publisherApps(id int) []map[string]interface{} {
+	items := []map[string]interface{}{}
+	for _, appID := range sortedIDs(s.apps) {
+		app := s.apps[appID]
+		for i, pubID := range app.PublisherIDs {
+			if pubID != id {
+				continue
+			}
+			items = append(items, map[string]interface{}{
+				"app_id":            app.ID,
+				"app_name":          app.Name,
+				"host":              app.Host,
+				"clientless_access": app.ClientlessAccess,
+				"protocols":         app.protocols("type"),
+				"primary":           strconv.FormatBool(i == 0),
+				"reachability":      app.reachability(),
+			})
+		}
+	}
+	return items
+}
+
+// serveBulkPublishers applies an upgrade request to several publishers.
+func (s *Server) serveBulkPublishers(w http.ResponseWriter, r *http.Request, body []byte) {
+	if r.Method != http.MethodPut {
+		writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req struct {
+		Publishers struct {
+			Apply struct {
+				UpgradeRequest bool `json:"upgrade_request"`
+			} `json:"apply"`
+			ID []json.Number `json:"id"`
+		} `json:"publishers"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || len(req.Publishers.ID) == 0 {
+		writeError(w, false, http.StatusBadRequest, "publisher ids are required")
+		return
+	}
+	items := []map[string]interface{}{}
+	for _, n := range req.Publishers.ID {
+		id, _ := parseID(n.String())
+		p, ok := s.publisher[id]
+		if !ok {
+			continue
+		}
+		p.UpgradeRequest = req.Publishers.Apply.UpgradeRequest
+		items = append(items, map[string]interface{}{"id": p.ID, "name": p.Name, "upgrade_request": p.UpgradeRequest})
+	}
+	writeSuccess(w, map[string]interface{}{"publishers": items}, -1)
+}
+
+func orEmpty[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
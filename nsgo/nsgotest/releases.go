@@ -0,0 +1,44 @@
+package nsgotest
+
+import "net/http"
+
+// Release is a publisher release served by the Server. The publishers running it are
+// those whose Version is the Version of the release.
+type Release struct {
+	Name        string
+	Version     string
+	DockerTag   string
+	ReleaseType string
+}
+
+// SetReleases replaces the publisher releases served by the Server, which serves none at first.
+func (s *Server) SetReleases(releases []Release) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.releases = append([]Release(nil), releases...)
+}
+
+func (s *Server) serveReleases(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	items := []map[string]interface{}{}
+	for _, release := range s.releases {
+		count := 0
+		for _, p := range s.publisher {
+			if release.Version != "" && p.Version == release.Version {
+				count++
+			}
+		}
+		items = append(items, map[string]interface{}{
+			"name":            release.Name,
+			"version":         release.Version,
+			"docker_tag":      release.DockerTag,
+			"release_type":    release.ReleaseType,
+			"publisher_count": count,
+		})
+	}
+	writeSuccess(w, items, -1)
+}
@@ -0,0 +1,372 @@
+// Package nsgotest provides an in-memory fake of the Netskope v2 API for testing code built on nsgo.
+//
+// The fake keeps publishers, publisher upgrade profiles, private apps and IPSec tunnels in memory
+// and serves them with the same envelopes, filtering and pagination as a tenant. Failures and
+// latency can be injected to exercise error handling and retries.
+//
+//	srv := nsgotest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddPublisher(nsgotest.Publisher{Name: "pub-1", Status: "connected", Registered: true})
+//	nsclient := srv.Client()
+//	pubs, err := nsclient.Publishers.List(ctx)
+package nsgotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+)
+
+// DefaultToken is the API token expected by a new Server and used by Server.Client.
+const DefaultToken = "nsgotest-token"
+
+// Server is a fake Netskope tenant. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Token is the API token requests must carry. An empty Token accepts any request.
+	Token string
+
+	mu        sync.Mutex
+	nextID    int
+	latency   time.Duration
+	pageSize  int
+	failures  []*Failure
+	requests  []Request
+	publisher map[int]*Publisher
+	profiles  map[int]*UpgradeProfile
+	apps      map[int]*PrivateApp
+	pops      []IpsecPop
+	tunnels   map[int]*IpsecTunnel
+	releases  []Release
+	alerts    AlertsConfiguration
+}
+
+// Request is a request received by the Server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Failure describes requests the Server answers with an error instead of serving them.
+type Failure struct {
+	// Method and Path select the requests to fail. An empty Method matches any method,
+	// and Path matches every path it is a prefix of.
+	Method string
+	Path   string
+	// Status is the HTTP status code to answer with.
+	Status int
+	// Message is sent as the error message. It defaults to the status text.
+	Message string
+	// RetryAfter, if set, is sent in the Retry-After header.
+	RetryAfter time.Duration
+	// Times is the number of requests to fail. Zero means one.
+	Times int
+	// AfterServing serves the request before answering with the error, as when a gateway
+	// times out after the tenant processed a request.
+	AfterServing bool
+}
+
+// NewServer starts a Server seeded with a couple of IPSec PoPs. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		Token:     DefaultToken,
+		nextID:    1,
+		publisher: map[int]*Publisher{},
+		profiles:  map[int]*UpgradeProfile{},
+		apps:      map[int]*PrivateApp{},
+		tunnels:   map[int]*IpsecTunnel{},
+		pops: []IpsecPop{
+			{ID: "1", Name: "US-SJC1", Gateway: "163.116.128.80", Probeip: "10.136.1.1", Region: "US", Country: "US", Location: "San Jose"},
+			{ID: "2", Name: "DE-FRA1", Gateway: "163.116.146.80", Probeip: "10.136.2.1", Region: "EU", Country: "DE", Location: "Frankfurt"},
+		},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an nsgo client for the Server, with opts applied after the base URL and token.
+func (s *Server) Client(opts ...nsgo.Option) *nsgo.Client {
+	s.mu.Lock()
+	token := s.Token
+	s.mu.Unlock()
+
+	c, err := nsgo.New(s.URL, append([]nsgo.Option{nsgo.WithAPIToken(token)}, opts...)...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Fail makes the Server answer the requests selected by f with an error.
+// Failures are matched in the order they were added.
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Times <= 0 {
+		f.Times = 1
+	}
+	s.failures = append(s.failures, &f)
+}
+
+// SetToken changes the API token requests must carry while the Server is running,
+// as when a token is rotated.
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Token = token
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// SetMaxPageSize caps the pages of list endpoints to n items, whatever the limit requested,
+// as tenants do. Zero removes the cap.
+func (s *Server) SetMaxPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pageSize = n
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// newID returns a fresh identifier. Callers hold s.mu.
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	latency := s.latency
+	failure := s.takeFailure(r)
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	ipsec := strings.HasPrefix(r.URL.Path, "/api/v2/steering/ipsec/")
+	if failure != nil {
+		if failure.AfterServing {
+			s.route(httptest.NewRecorder(), r, body)
+		}
+		if failure.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((failure.RetryAfter+time.Second-1)/time.Second)))
+		}
+		message := failure.Message
+		if message == "" {
+			message = http.StatusText(failure.Status)
+		}
+		writeError(w, ipsec, failure.Status, message)
+		return
+	}
+	s.route(w, r, body)
+}
+
+// route authenticates r and serves it from the stored objects.
+func (s *Server) route(w http.ResponseWriter, r *http.Request, body []byte) {
+	ipsec := strings.HasPrefix(r.URL.Path, "/api/v2/steering/ipsec/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Token != "" && r.Header.Get("Netskope-Api-Token") != s.Token {
+		writeError(w, ipsec, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "api" || parts[1] != "v2" {
+		writeError(w, ipsec, http.StatusNotFound, "not found")
+		return
+	}
+	route := strings.Join(parts[2:4], "/")
+	rest := parts[4:]
+	switch {
+	case route == "infrastructure/publishers":
+		s.servePublishers(w, r, rest, body)
+	case route == "infrastructure/publisherupgradeprofiles":
+		s.serveProfiles(w, r, rest, body)
+	case route == "steering/apps" && len(rest) > 0 && rest[0] == "private":
+		s.servePrivateApps(w, r, rest[1:], body)
+	case route == "steering/ipsec" && len(rest) > 0 && rest[0] == "pops":
+		s.servePops(w, r, rest[1:])
+	case route == "steering/ipsec" && len(rest) > 0 && rest[0] == "tunnels":
+		s.serveTunnels(w, r, rest[1:], body)
+	default:
+		writeError(w, ipsec, http.StatusNotFound, "not found")
+	}
+}
+
+// takeFailure returns the failure to answer r with, if any. Callers hold s.mu.
+func (s *Server) takeFailure(r *http.Request) *Failure {
+	for i, f := range s.failures {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		f.Times--
+		if f.Times <= 0 {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// writeSuccess writes the "success" envelope used by most endpoints.
+func writeSuccess(w http.ResponseWriter, data interface{}, total int) {
+	res := map[string]interface{}{"status": "success", "data": data}
+	if total >= 0 {
+		res["total"] = total
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// writeIpsec writes the integer status envelope used by the IPSec endpoints.
+func writeIpsec(w http.ResponseWriter, status int, result interface{}, total int) {
+	res := map[string]interface{}{"status": status, "result": result}
+	if total >= 0 {
+		res["total"] = total
+	}
+	writeJSON(w, status, res)
+}
+
+func writeError(w http.ResponseWriter, ipsec bool, status int, message string) {
+	if ipsec {
+		writeJSON(w, status, map[string]interface{}{"status": status, "message": message})
+		return
+	}
+	writeJSON(w, status, map[string]interface{}{"status": "error", "message": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// fields decodes a JSON object body, keeping track of which keys were sent.
+func fields(body []byte) (map[string]json.RawMessage, error) {
+	m := map[string]json.RawMessage{}
+	if len(body) == 0 {
+		return m, nil
+	}
+	err := json.Unmarshal(body, &m)
+	return m, err
+}
+
+// set decodes m[key] into v when the key was sent.
+func set(m map[string]json.RawMessage, key string, v interface{}) {
+	if raw, ok := m[key]; ok {
+		json.Unmarshal(raw, v)
+	}
+}
+
+func parseID(s string) (int, bool) {
+	id, err := strconv.Atoi(s)
+	return id, err == nil
+}
+
+func sortedIDs[T any](m map[int]T) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// page applies the offset and limit query parameters to items, returning at most maxSize items
+// when maxSize is positive.
+func page[T any](q url.Values, items []T, maxSize int) []T {
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 || (maxSize > 0 && limit > maxSize) {
+		limit = maxSize
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// matchQuery reports whether an item with the given fields matches a query filter.
+// Filters are clauses such as `publisher_name eq "pub-1"` joined by "and"; any other
+// filter matches items whose name contains it.
+func matchQuery(query, name string, values map[string]string) bool {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return true
+	}
+	for _, clause := range strings.Split(query, " and ") {
+		parts := strings.SplitN(strings.TrimSpace(clause), " ", 3)
+		if len(parts) != 3 || (parts[1] != "eq" && parts[1] != "has") {
+			if !strings.Contains(name, strings.Trim(clause, `" `)) {
+				return false
+			}
+			continue
+		}
+		want := strings.Trim(parts[2], `"'`)
+		got, ok := values[parts[0]]
+		if !ok {
+			return false
+		}
+		if parts[1] == "eq" && got != want || parts[1] == "has" && !strings.Contains(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func notFound(kind string, id string) string {
+	return fmt.Sprintf("%s %s not found", kind, id)
+}
@@ -0,0 +1,58 @@
+package nsgotest
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestServerFailure(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.Fail(Failure{Method: http.MethodPost, Path: "/api/v2/infrastructure", Status: http.StatusBadGateway, Times: 2})
+	get := func(method, path string) int {
+		req, _ := http.NewRequest(method, srv.URL+path, nil)
+		req.Header.Set("Netskope-Api-Token", srv.Token)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	if got := get(http.MethodGet, "/api/v2/infrastructure/publishers"); got != http.StatusOK {
+		t.Errorf("GET publishers = %d, want 200", got)
+	}
+	for i := 0; i < 2; i++ {
+		if got := get(http.MethodPost, "/api/v2/infrastructure/publishers"); got != http.StatusBadGateway {
+			t.Errorf("POST publishers #%d = %d, want 502", i, got)
+		}
+	}
+	if got := get(http.MethodPost, "/api/v2/infrastructure/publishers"); got != http.StatusBadRequest {
+		t.Errorf("POST publishers after failures = %d, want 400", got)
+	}
+	if got := len(srv.Requests()); got != 4 {
+		t.Errorf("Requests() returned %d requests, want 4", got)
+	}
+}
+
+// Run with -race: Client reads the token that SetToken writes.
+func TestServerSetTokenConcurrent(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			srv.SetToken("rotated-token")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		srv.Client()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,158 @@
+package nsgotest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// UpgradeProfile is a publisher upgrade profile stored by the Server.
+type UpgradeProfile struct {
+	ID             int
+	ExternalID     int
+	Name           string
+	Timezone       string
+	ReleaseType    string
+	DockerTag      string
+	Frequency      string
+	Enabled        bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	NextUpdateTime time.Time
+}
+
+// AddUpgradeProfile stores p, assigning it IDs when it has none, and returns its ID.
+func (s *Server) AddUpgradeProfile(p UpgradeProfile) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addProfile(p)
+}
+
+func (s *Server) addProfile(p UpgradeProfile) int {
+	if p.ID == 0 {
+		p.ID = s.newID()
+	}
+	if p.ExternalID == 0 {
+		p.ExternalID = p.ID
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now().UTC().Truncate(time.Second)
+		p.UpdatedAt = p.CreatedAt
+	}
+	s.profiles[p.ID] = &p
+	return p.ID
+}
+
+func (s *Server) profileView(p *UpgradeProfile) map[string]interface{} {
+	associated := 0
+	for _, pub := range s.publisher {
+		if pub.UpgradeProfileID == p.ExternalID {
+			associated++
+		}
+	}
+	var next int64
+	if !p.NextUpdateTime.IsZero() {
+		next = p.NextUpdateTime.Unix()
+	}
+	return map[string]interface{}{
+		"id":                       p.ID,
+		"external_id":              p.ExternalID,
+		"name":                     p.Name,
+		"timezone":                 p.Timezone,
+		"release_type":             p.ReleaseType,
+		"docker_tag":               p.DockerTag,
+		"frequency":                p.Frequency,
+		"enabled":                  p.Enabled,
+		"created_at":               p.CreatedAt.Format("2006-01-02 15:04:05"),
+		"updated_at":               p.UpdatedAt.Format("2006-01-02 15:04:05"),
+		"next_update_time":         next,
+		"num_associated_publisher": associated,
+		"upgrading_stage":          0,
+		"will_start":               p.Enabled,
+	}
+}
+
+// applyProfile updates p with the fields sent in body.
+func applyProfile(p *UpgradeProfile, body []byte) error {
+	m, err := fields(body)
+	if err != nil {
+		return err
+	}
+	set(m, "name", &p.Name)
+	set(m, "timezone", &p.Timezone)
+	set(m, "release_type", &p.ReleaseType)
+	set(m, "docker_tag", &p.DockerTag)
+	set(m, "frequency", &p.Frequency)
+	set(m, "enabled", &p.Enabled)
+	p.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	return nil
+}
+
+// findProfile looks a profile up by ID or external ID. Callers hold s.mu.
+func (s *Server) findProfile(key string) (*UpgradeProfile, bool) {
+	id, ok := parseID(key)
+	if !ok {
+		return nil, false
+	}
+	if p, ok := s.profiles[id]; ok {
+		return p, true
+	}
+	for _, p := range s.profiles {
+		if p.ExternalID == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) serveProfiles(w http.ResponseWriter, r *http.Request, rest []string, body []byte) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var items []map[string]interface{}
+			for _, id := range sortedIDs(s.profiles) {
+				p := s.profiles[id]
+				values := map[string]string{"name": p.Name, "id": strconv.Itoa(p.ID)}
+				if matchQuery(r.URL.Query().Get("query"), p.Name, values) {
+					items = append(items, s.profileView(p))
+				}
+			}
+			total := len(items)
+			writeSuccess(w, map[string]interface{}{"upgrade_profiles": orEmpty(page(r.URL.Query(), items, s.pageSize))}, total)
+		case http.MethodPost:
+			p := UpgradeProfile{}
+			if err := applyProfile(&p, body); err != nil || p.Name == "" {
+				writeError(w, false, http.StatusBadRequest, "a profile name is required")
+				return
+			}
+			id := s.addProfile(p)
+			writeSuccess(w, s.profileView(s.profiles[id]), -1)
+		default:
+			writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	p, ok := s.findProfile(rest[0])
+	if !ok || len(rest) != 1 {
+		writeError(w, false, http.StatusNotFound, notFound("upgrade profile", rest[0]))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeSuccess(w, s.profileView(p), -1)
+	case http.MethodPut, http.MethodPatch:
+		if err := applyProfile(p, body); err != nil {
+			writeError(w, false, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeSuccess(w, s.profileView(p), -1)
+	case http.MethodDelete:
+		delete(s.profiles, p.ID)
+		writeSuccess(w, nil, -1)
+	default:
+		writeError(w, false, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,143 @@
+package nsgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestInfo describes a request sent by a Client, for Observers.
+type RequestInfo struct {
+	Method string
+	// Route is the path of the request with its identifiers replaced by {id},
+	// i.e. "/api/v2/steering/apps/private/{id}", to group requests by endpoint.
+	Route string
+	// Path is the path of the request, i.e. "/api/v2/steering/apps/private/42".
+	Path string
+	// Attempt is the number of the attempt, starting at 1, when the client retries.
+	Attempt int
+}
+
+// An Observer is notified of the requests sent by a Client, i.e. to collect metrics or traces.
+// Its methods are called synchronously, possibly from several goroutines, and should return quickly.
+type Observer interface {
+	// RequestStart is called when a call starts, before its first attempt.
+	RequestStart(ctx context.Context, info RequestInfo)
+	// RequestRetry is called before every attempt after the first.
+	RequestRetry(ctx context.Context, info RequestInfo)
+	// RequestResponse is called when a call got a response, whatever its status.
+	RequestResponse(ctx context.Context, info RequestInfo, status int, duration time.Duration)
+	// RequestError is called when a call got no response, i.e. because of a network
+	// failure or because ctx was cancelled.
+	RequestError(ctx context.Context, info RequestInfo, err error, duration time.Duration)
+}
+
+// Hooks is an Observer calling its non-nil fields.
+type Hooks struct {
+	OnStart    func(ctx context.Context, info RequestInfo)
+	OnRetry    func(ctx context.Context, info RequestInfo)
+	OnResponse func(ctx context.Context, info RequestInfo, status int, duration time.Duration)
+	OnError    func(ctx context.Context, info RequestInfo, err error, duration time.Duration)
+}
+
+// RequestStart implements Observer.
+func (h Hooks) RequestStart(ctx context.Context, info RequestInfo) {
+	if h.OnStart != nil {
+		h.OnStart(ctx, info)
+	}
+}
+
+// RequestRetry implements Observer.
+func (h Hooks) RequestRetry(ctx context.Context, info RequestInfo) {
+	if h.OnRetry != nil {
+		h.OnRetry(ctx, info)
+	}
+}
+
+// RequestResponse implements Observer.
+func (h Hooks) RequestResponse(ctx context.Context, info RequestInfo, status int, duration time.Duration) {
+	if h.OnResponse != nil {
+		h.OnResponse(ctx, info, status, duration)
+	}
+}
+
+// RequestError implements Observer.
+func (h Hooks) RequestError(ctx context.Context, info RequestInfo, err error, duration time.Duration) {
+	if h.OnError != nil {
+		h.OnError(ctx, info, err, duration)
+	}
+}
+
+// WithObserver notifies obs of every request sent by the client. It can be used several times.
+func WithObserver(obs Observer) Option {
+	return func(o *clientOptions) error {
+		if obs == nil {
+			return errors.New("nsgo: nil observer")
+		}
+		o.observers = append(o.observers, obs)
+		return nil
+	}
+}
+
+// observers notifies several Observers.
+type observers []Observer
+
+func (o observers) RequestStart(ctx context.Context, info RequestInfo) {
+	for _, obs := range o {
+		obs.RequestStart(ctx, info)
+	}
+}
+
+func (o observers) RequestRetry(ctx context.Context, info RequestInfo) {
+	for _, obs := range o {
+		obs.RequestRetry(ctx, info)
+	}
+}
+
+func (o observers) RequestResponse(ctx context.Context, info RequestInfo, status int, duration time.Duration) {
+	for _, obs := range o {
+		obs.RequestResponse(ctx, info, status, duration)
+	}
+}
+
+func (o observers) RequestError(ctx context.Context, info RequestInfo, err error, duration time.Duration) {
+	for _, obs := range o {
+		obs.RequestError(ctx, info, err, duration)
+	}
+}
+
+// requestInfo returns the RequestInfo of req.
+func requestInfo(req *http.Request) RequestInfo {
+	return RequestInfo{
+		Method:  req.Method,
+		Route:   routeTemplate(req.URL.Path),
+		Path:    req.URL.Path,
+		Attempt: attemptOf(req),
+	}
+}
+
+// routeTemplate replaces the identifiers in path by {id}. The segments of the API paths
+// are lower case words, so any other segment after the version is taken for an identifier.
+func routeTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if s == "" || (i <= 2 && (s == "api" || s == "v1" || s == "v2")) {
+			continue
+		}
+		if !isWord(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isWord(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
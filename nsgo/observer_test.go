@@ -0,0 +1,68 @@
+package nsgo_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestObserver(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var events []string
+	record := func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, fmt.Sprintf(format, args...))
+	}
+	hooks := nsgo.Hooks{
+		OnStart: func(ctx context.Context, info nsgo.RequestInfo) {
+			record("start %s %s", info.Method, info.Route)
+		},
+		OnRetry: func(ctx context.Context, info nsgo.RequestInfo) {
+			record("retry %s %s %d", info.Method, info.Route, info.Attempt)
+		},
+		OnResponse: func(ctx context.Context, info nsgo.RequestInfo, status int, duration time.Duration) {
+			record("response %s %s %d %d", info.Method, info.Route, status, info.Attempt)
+		},
+		OnError: func(ctx context.Context, info nsgo.RequestInfo, err error, duration time.Duration) {
+			record("error %s %s", info.Method, info.Route)
+		},
+	}
+	nsclient := srv.Client(nsgo.WithObserver(hooks), nsgo.WithRetry(nsgo.RetryConfig{RetryMax: 2}))
+	ctx := context.Background()
+
+	id := srv.AddPrivateApp(nsgotest.PrivateApp{Name: "wiki", Host: "wiki.internal"})
+	srv.Fail(nsgotest.Failure{Path: "/api/v2/steering/apps/private", Status: http.StatusBadGateway})
+	if _, err := nsclient.PrivateApps.Get(ctx, nsgo.PrivateAppOptions{Id: strconv.Itoa(id)}); err != nil {
+		t.Fatalf("PrivateApps.Get: %v", err)
+	}
+	if _, err := nsclient.IPsec.GetPop(ctx, nsgo.RequestOptions{Id: "1"}); err != nil {
+		t.Fatalf("IPsec.GetPop: %v", err)
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	nsclient.Publishers.List(cancelled)
+
+	want := []string{
+		"start GET /api/v2/steering/apps/private/{id}",
+		"retry GET /api/v2/steering/apps/private/{id} 2",
+		"response GET /api/v2/steering/apps/private/{id} 200 2",
+		"start GET /api/v2/steering/ipsec/pops/{id}",
+		"response GET /api/v2/steering/ipsec/pops/{id} 200 1",
+		"start GET /api/v2/infrastructure/publishers",
+		"error GET /api/v2/infrastructure/publishers",
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events:\n%q\nwant:\n%q", events, want)
+	}
+}
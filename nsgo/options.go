@@ -0,0 +1,224 @@
+package nsgo
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultUserAgent is sent with every request, followed by any suffix set with WithUserAgent.
+const defaultUserAgent = "nsgo-api-client/0.3.0"
+
+// An Option configures a Client built by New.
+type Option func(*clientOptions) error
+
+type clientOptions struct {
+	credentials CredentialsProvider
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	timeout     *time.Duration
+	userAgent   string
+	proxy       func(*http.Request) (*url.URL, error)
+	retry       *RetryPolicy
+	retryLogger interface{}
+	logger      interface{}
+	harTracer   *HARTracer
+	observers   observers
+
+	rateLimits  map[string]RateLimit
+	noRateLimit bool
+}
+
+// WithAPIToken sets the Netskope API v2 token sent with every request.
+// Use WithCredentials for tokens that are rotated.
+func WithAPIToken(token string) Option {
+	return WithCredentials(StaticCredentials(token))
+}
+
+// WithHTTPClient uses hc as the base HTTP client. Its transport and timeout are
+// kept unless overridden by WithTransport or WithTimeout; hc itself is not modified.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *clientOptions) error {
+		if hc == nil {
+			return errors.New("nsgo: nil http client")
+		}
+		o.httpClient = hc
+		return nil
+	}
+}
+
+// WithTransport sets the RoundTripper used to reach the tenant.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *clientOptions) error {
+		if rt == nil {
+			return errors.New("nsgo: nil transport")
+		}
+		o.transport = rt
+		return nil
+	}
+}
+
+// WithTimeout limits the time spent on a single call, retries included.
+// A zero timeout means no limit.
+func WithTimeout(d time.Duration) Option {
+	return func(o *clientOptions) error {
+		if d < 0 {
+			return errors.New("nsgo: negative timeout")
+		}
+		o.timeout = &d
+		return nil
+	}
+}
+
+// WithUserAgent appends suffix to the User-Agent header sent by the client.
+func WithUserAgent(suffix string) Option {
+	return func(o *clientOptions) error {
+		o.userAgent = suffix
+		return nil
+	}
+}
+
+// WithProxy routes requests through the proxy at rawURL.
+// It applies only when the transport in use is an *http.Transport.
+func WithProxy(rawURL string) Option {
+	return func(o *clientOptions) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return err
+		}
+		o.proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// WithRetry enables automatic retries, as done by NewRetryClient. It is WithRetryPolicy with
+// DefaultRetryPolicy, the retry count and waits of config, and no budget.
+func WithRetry(config RetryConfig) Option {
+	return func(o *clientOptions) error {
+		if err := WithRetryPolicy(config.policy())(o); err != nil {
+			return err
+		}
+		o.retryLogger = config.Logger
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the client. It accepts the same values as RetryConfig.Logger
+// and takes precedence over it.
+//
+// A *slog.Logger logs every request at the debug level (method, path, status, duration, attempt
+// and remaining rate limit), and retries and unexpected responses at the warning level. It also
+// receives the logs of the retrying client, through a LeveledLogger. Headers, and so the API
+// token, are never logged.
+//
+// Other values, a retryablehttp.Logger or retryablehttp.LeveledLogger, are only handed to the
+// retrying client.
+func WithLogger(logger interface{}) Option {
+	return func(o *clientOptions) error {
+		o.logger = logger
+		return nil
+	}
+}
+
+// New returns a Client for the Netskope tenant at baseURL (i.e. "https://example-tenant.goskope.com").
+// The client does not throttle itself unless WithRateLimit is given.
+//
+//	nsclient, err := nsgo.New(os.Getenv("NS_BaseURL"),
+//		nsgo.WithAPIToken(os.Getenv("NS_ApiToken")),
+//		nsgo.WithTimeout(30*time.Second),
+//		nsgo.WithRetry(nsgo.RetryConfig{RetryMax: 5, RetryWaitMin: 1, RetryWaitMax: 10}),
+//	)
+func New(baseURL string, opts ...Option) (*Client, error) {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	hc := &http.Client{Timeout: time.Minute}
+	if o.httpClient != nil {
+		copied := *o.httpClient
+		hc = &copied
+	}
+	if o.timeout != nil {
+		hc.Timeout = *o.timeout
+	}
+
+	transport := o.transport
+	if transport == nil {
+		transport = hc.Transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if o.proxy != nil {
+		if t, ok := transport.(*http.Transport); ok {
+			t = t.Clone()
+			t.Proxy = o.proxy
+			transport = t
+		}
+	}
+	var logger *slog.Logger
+	retryLogger := o.logger
+	if retryLogger == nil {
+		retryLogger = o.retryLogger
+	}
+	if l, ok := retryLogger.(*slog.Logger); ok && l != nil {
+		logger = l
+		retryLogger = LeveledLogger{Logger: l}
+	}
+
+	if o.harTracer != nil {
+		transport = &harTransport{next: transport, tracer: o.harTracer}
+	}
+	if logger != nil {
+		transport = &logTransport{next: transport, logger: logger}
+	} else {
+		logger = slog.New(discardHandler{})
+	}
+	var limiter *rateLimiter
+	if o.rateLimits != nil && !o.noRateLimit {
+		limiter = newRateLimiter(o.rateLimits)
+		transport = &rateLimitTransport{next: transport, limiter: limiter}
+	}
+	var observer Observer
+	if len(o.observers) > 0 {
+		observer = o.observers
+	}
+	transport = &attemptTransport{next: transport, observer: observer}
+	var budget *retryBudget
+	if o.retry != nil {
+		budget = newRetryBudget(o.retry.Budget)
+		transport = newRetryTransport(transport, o.retry, budget, logger, retryLogger)
+	}
+	hc.Transport = transport
+
+	userAgent := defaultUserAgent
+	if o.userAgent != "" {
+		userAgent += " " + o.userAgent
+	}
+
+	if o.credentials == nil {
+		o.credentials = StaticCredentials("")
+	}
+
+	c := &Client{
+		BaseURL:     baseURL,
+		credentials: o.credentials,
+		HttpClient:  hc,
+		userAgent:   userAgent,
+		limiter:     limiter,
+		logger:      logger,
+		observer:    observer,
+		retry:       o.retry,
+		retryBudget: budget,
+	}
+	c.Publishers = &publishersService{c}
+	c.PrivateApps = &privateAppsService{c}
+	c.IPsec = &ipsecService{c}
+	c.UpgradeProfiles = &upgradeProfilesService{c}
+	return c, nil
+}
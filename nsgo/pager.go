@@ -0,0 +1,130 @@
+package nsgo
+
+import (
+	"context"
+
+	"github.com/google/go-querystring/query"
+)
+
+// defaultPageSize is the page size used when ListOptions.Limit is not set.
+const defaultPageSize = 100
+
+// ListOptions controls paging, sorting and field selection on list endpoints.
+//
+// - Offset: the index of the first item to return
+//
+// - Limit: the page size
+//
+// - Sort: the sort order, as accepted by the endpoint (i.e. "name" or "-id")
+//
+// - Fields: a comma separated list of the fields to return
+//
+// - Query: a filter, as accepted by GetPublishersWithFilter and GetPrivateAppsWithFilter
+type ListOptions struct {
+	Offset int    `url:"offset,omitempty"`
+	Limit  int    `url:"limit,omitempty"`
+	Sort   string `url:"sort,omitempty"`
+	Fields string `url:"fields,omitempty"`
+	Query  string `url:"query,omitempty"`
+}
+
+// A Pager walks the pages of a list endpoint, using the total reported by the API to know when to stop,
+// or a page shorter than ListOptions.Limit when the endpoint reports no total.
+//
+//	pager := nsclient.PrivateApps.Pages(nsgo.ListOptions{Limit: 500})
+//	for pager.More() {
+//		apps, err := pager.Next(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+type Pager[T any] struct {
+	fetch func(ctx context.Context, opts ListOptions) ([]T, int, error)
+	opts  ListOptions
+	total int
+	done  bool
+}
+
+// NewPager returns a Pager calling fetch for each page, i.e. to fake a list endpoint.
+// fetch returns the items of the page selected by opts and the total number of items.
+func NewPager[T any](opts ListOptions, fetch func(ctx context.Context, opts ListOptions) ([]T, int, error)) *Pager[T] {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultPageSize
+	}
+	return &Pager[T]{fetch: fetch, opts: opts, total: -1}
+}
+
+// More reports whether there may be more pages to fetch.
+func (p *Pager[T]) More() bool {
+	return !p.done
+}
+
+// Total returns the total number of items reported by the API, or -1 before the first page was fetched
+// or when the endpoint does not report it.
+func (p *Pager[T]) Total() int {
+	return p.total
+}
+
+// Next fetches the next page. It returns an empty page once there are no more items.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	items, total, err := p.fetch(ctx, p.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p.opts.Offset += len(items)
+	if total > 0 {
+		p.total = total
+	}
+	// Tenants may cap the page size below Limit, so short pages only end the list when
+	// the endpoint does not report its total.
+	switch {
+	case len(items) == 0:
+		p.done = true
+	case p.total >= 0:
+		p.done = p.opts.Offset >= p.total
+	case len(items) < p.opts.Limit:
+		p.done = true
+	}
+	return items, nil
+}
+
+// All fetches every remaining page and returns their items.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for p.More() {
+		items, err := p.Next(ctx)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// listFetcher returns a Pager fetch function for the list endpoint at path, whose payload decodes into a P
+// holding the items returned by items.
+func listFetcher[P, T any](c *Client, path string, items func(*P) []T) func(context.Context, ListOptions) ([]T, int, error) {
+	return func(ctx context.Context, opts ListOptions) ([]T, int, error) {
+		values, err := query.Values(opts)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		payload, total, err := c.do(ctx, "GET", path+"?"+values.Encode(), nil)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var page P
+		if err := decodePayload(payload, &page); err != nil {
+			return nil, 0, err
+		}
+		return items(&page), total, nil
+	}
+}
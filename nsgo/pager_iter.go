@@ -0,0 +1,35 @@
+//go:build go1.23
+
+package nsgo
+
+import (
+	"context"
+	"iter"
+)
+
+// Items returns an iterator over every remaining item, fetching pages as needed.
+// Iteration stops after yielding the first error.
+//
+//	for app, err := range nsclient.PrivateApps.Pages(nsgo.ListOptions{}).Items(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (p *Pager[T]) Items(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p.More() {
+			items, err := p.Next(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
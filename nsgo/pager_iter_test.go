@@ -0,0 +1,57 @@
+//go:build go1.23
+
+package nsgo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestPagerItems(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	for i := 0; i < 5; i++ {
+		srv.AddPublisher(nsgotest.Publisher{Name: fmt.Sprintf("pub-%d", i)})
+	}
+	nsclient := srv.Client(nsgo.WithoutRateLimit())
+	ctx := context.Background()
+
+	var names []string
+	for p, err := range nsclient.Publishers.Pages(nsgo.ListOptions{Limit: 2}).Items(ctx) {
+		if err != nil {
+			t.Fatalf("Items: %v", err)
+		}
+		names = append(names, p.PublisherName)
+	}
+	if fmt.Sprint(names) != "[pub-0 pub-1 pub-2 pub-3 pub-4]" {
+		t.Errorf("Items = %v", names)
+	}
+
+	// Breaking out of the loop leaves the rest of the pages to the pager.
+	pager := nsclient.Publishers.Pages(nsgo.ListOptions{Limit: 2})
+	for range pager.Items(ctx) {
+		break
+	}
+	rest, err := pager.All(ctx)
+	if err != nil || len(rest) != 3 {
+		t.Errorf("All after a break = %d publishers, %v; want 3", len(rest), err)
+	}
+
+	// An error is yielded once, and ends the iteration.
+	errPage := errors.New("page failed")
+	failing := nsgo.NewPager(nsgo.ListOptions{}, func(ctx context.Context, opts nsgo.ListOptions) ([]int, int, error) {
+		return nil, 0, errPage
+	})
+	var errs []error
+	for _, err := range failing.Items(ctx) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], errPage) {
+		t.Errorf("Items errors = %v, want [%v]", errs, errPage)
+	}
+}
@@ -0,0 +1,75 @@
+package nsgo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestPager(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	for i := 0; i < 7; i++ {
+		srv.AddPublisher(nsgotest.Publisher{Name: fmt.Sprintf("pub-%d", i)})
+	}
+	ctx := context.Background()
+
+	pager := srv.Client().Publishers.Pages(nsgo.ListOptions{Limit: 3})
+	var pages []int
+	for pager.More() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		pages = append(pages, len(page))
+	}
+	if fmt.Sprint(pages) != "[3 3 1]" || pager.Total() != 7 {
+		t.Errorf("pages = %v, total = %d; want [3 3 1] and 7", pages, pager.Total())
+	}
+
+	all, err := srv.Client().Publishers.Pages(nsgo.ListOptions{Limit: 2, Query: `publisher_name has "pub-1"`}).All(ctx)
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != 1 || all[0].PublisherName != "pub-1" {
+		t.Errorf("All = %+v", all)
+	}
+
+	empty, err := srv.Client().IPsec.TunnelPages(nsgo.ListOptions{}).All(ctx)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("IPsec.TunnelPages.All = %v, %v; want no tunnels", empty, err)
+	}
+}
+
+func TestPagerCappedPages(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	for i := 0; i < 10; i++ {
+		srv.AddPublisher(nsgotest.Publisher{Name: fmt.Sprintf("pub-%d", i)})
+	}
+	srv.SetMaxPageSize(2)
+	ctx := context.Background()
+
+	// The tenant returns 2 items per page, not 5, and the total tells there are more.
+	all, err := srv.Client(nsgo.WithoutRateLimit()).Publishers.Pages(nsgo.ListOptions{Limit: 5}).All(ctx)
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != 10 {
+		t.Errorf("All returned %d publishers, want 10", len(all))
+	}
+
+	// Without a total, a short page is the last one.
+	calls := 0
+	pager := nsgo.NewPager(nsgo.ListOptions{Limit: 5}, func(ctx context.Context, opts nsgo.ListOptions) ([]int, int, error) {
+		calls++
+		return []int{1, 2}, 0, nil
+	})
+	items, err := pager.All(ctx)
+	if err != nil || len(items) != 2 || calls != 1 {
+		t.Errorf("All without a total = %v, %v after %d calls; want 2 items from 1 call", items, err, calls)
+	}
+}
@@ -1,12 +1,11 @@
 package nsgo
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -15,53 +14,115 @@ type PrivateAppOptions struct {
 	Id   string `json:"id,omitempty"`
 }
 
+// PrivateAppsList defines the list of private apps returned from the GET method.
 type PrivateAppsList struct {
-	PrivateApps []struct {
-		AppID              int    `json:"app_id"`
-		AppName            string `json:"app_name"`
-		ClientlessAccess   bool   `json:"clientless_access"`
-		Host               string `json:"host"`
-		PrivateAppProtocol string `json:"private_app_protocol"`
-		Protocols          []struct {
-			CreatedAt time.Time `json:"created_at"`
-			ID        int       `json:"id"`
-			Port      string    `json:"port"`
-			ServiceID int       `json:"service_id"`
-			Transport string    `json:"transport"`
-			UpdatedAt time.Time `json:"updated_at"`
-		} `json:"protocols"`
-		Reachability struct {
-			ErrorCode   int    `json:"-"`
-			ErrorString string `json:"-"`
-			Reachable   bool   `json:"reachable"`
-		} `json:"reachability"`
-		//Reachability                interface{} `json:"-"`
-		ServicePublisherAssignments []struct {
-			Primary     string `json:"primary"`
-			PublisherID int    `json:"publisher_id"`
-			//Reachability string `json:"reachability"`
-			Reachability struct {
-				ErrorCode   int    `json:"-"`
-				ErrorString string `json:"-"`
-				Reachable   bool   `json:"reachable"`
-			} `json:"reachability"`
-			ServiceID int `json:"service_id"`
-		} `json:"service_publisher_assignments"`
-		TrustSelfSignedCerts bool `json:"trust_self_signed_certs"`
-		UsePublisherDNS      bool `json:"use_publisher_dns"`
-	} `json:"private_apps"`
+	PrivateApps []PrivateAppSummary `json:"private_apps"`
 }
 
+// PrivateAppSummary is an individual private app as returned in a PrivateAppsList.
+type PrivateAppSummary struct {
+	AppID                       FlexInt                      `json:"app_id"`
+	AppName                     string                       `json:"app_name"`
+	ClientlessAccess            FlexBool                     `json:"clientless_access"`
+	Host                        string                       `json:"host"`
+	PrivateAppProtocol          string                       `json:"private_app_protocol"`
+	Protocols                   []PrivateAppProtocol         `json:"protocols"`
+	Reachability                PrivateAppReachability       `json:"reachability"`
+	ServicePublisherAssignments []ServicePublisherAssignment `json:"service_publisher_assignments"`
+	TrustSelfSignedCerts        FlexBool                     `json:"trust_self_signed_certs"`
+	UsePublisherDNS             FlexBool                     `json:"use_publisher_dns"`
+}
+
+// PrivateAppProtocol is a port and transport served by a private app, as returned by the API.
+type PrivateAppProtocol struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        FlexInt   `json:"id"`
+	Port      string    `json:"port"`
+	ServiceID FlexInt   `json:"service_id"`
+	Transport string    `json:"transport"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UnmarshalJSON decodes the timestamps of the protocol, which the API sends in several date formats.
+func (p *PrivateAppProtocol) UnmarshalJSON(data []byte) error {
+	type plain PrivateAppProtocol
+	aux := struct {
+		*plain
+		CreatedAt json.RawMessage `json:"created_at"`
+		UpdatedAt json.RawMessage `json:"updated_at"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if p.CreatedAt, err = parseTimestamp(aux.CreatedAt); err != nil {
+		return err
+	}
+	if p.UpdatedAt, err = parseTimestamp(aux.UpdatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PrivateAppReachability reports whether a private app, or one of its publishers, can reach the app host.
+// ErrorCode and ErrorString explain why it cannot.
+type PrivateAppReachability struct {
+	ErrorCode   FlexInt  `json:"error_code,omitempty"`
+	ErrorString string   `json:"error_string,omitempty"`
+	Reachable   FlexBool `json:"reachable"`
+}
+
+// ServicePublisherAssignment links a private app to one of the publishers serving it.
+type ServicePublisherAssignment struct {
+	Primary      FlexString             `json:"primary"`
+	PublisherID  FlexInt                `json:"publisher_id"`
+	Reachability PrivateAppReachability `json:"reachability"`
+	ServiceID    FlexInt                `json:"service_id"`
+}
+
+// The API sends null or {} for a reachability it has not assessed yet.
+func (r *PrivateAppReachability) UnmarshalJSON(data []byte) error {
+	type plain PrivateAppReachability
+	return decodeObject(data, (*plain)(r))
+}
+
+// PrivateApp defines an individual private app. It is used to create and update private apps,
+// and is returned by the GET by ID, Create, Update and Replace methods.
+// Fields left unset are not sent, so Update only changes the fields that are set.
 type PrivateApp struct {
-	AppName              string              `json:"app_name"`
-	Id                   int                 `json:"id",omitempty`
-	Host                 string              `json:"host"`
-	Protocols            []Protocol          `json:"protocols"`
-	Publishers           []PublisherIdentity `json:"publishers,omitempty"`
-	Tags                 []PrivateAppTags    `json:"tags,omitempty"`
-	UsePublisherDNS      bool                `json:"use_publisher_dns,omitempty"`
-	ClientlessAccess     bool                `json:"clientless_access,omitempty"`
-	TrustSelfSignedCerts bool                `json:"trust_self_signed_certs,omitempty"`
+	AppName                     string                       `json:"app_name,omitempty"`
+	Id                          int                          `json:"id,omitempty"`
+	Host                        string                       `json:"host,omitempty"`
+	Protocols                   []Protocol                   `json:"protocols,omitempty"`
+	Publishers                  []PublisherIdentity          `json:"publishers,omitempty"`
+	Tags                        []PrivateAppTags             `json:"tags,omitempty"`
+	UsePublisherDNS             *bool                        `json:"use_publisher_dns,omitempty"`
+	ClientlessAccess            *bool                        `json:"clientless_access,omitempty"`
+	TrustSelfSignedCerts        *bool                        `json:"trust_self_signed_certs,omitempty"`
+	Reachability                *PrivateAppReachability      `json:"-"` // Read only
+	ServicePublisherAssignments []ServicePublisherAssignment `json:"-"` // Read only
+}
+
+// UnmarshalJSON also accepts the app_id key used by the GET endpoints in place of id, and decodes
+// the read only fields, which are never sent back.
+func (p *PrivateApp) UnmarshalJSON(data []byte) error {
+	type plain PrivateApp
+	aux := struct {
+		*plain
+		AppID                       FlexInt                      `json:"app_id"`
+		Reachability                *PrivateAppReachability      `json:"reachability"`
+		ServicePublisherAssignments []ServicePublisherAssignment `json:"service_publisher_assignments"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if p.Id == 0 {
+		p.Id = int(aux.AppID)
+	}
+	p.Reachability = aux.Reachability
+	p.ServicePublisherAssignments = aux.ServicePublisherAssignments
+	return nil
 }
 
 type Protocol struct {
@@ -69,215 +130,211 @@ type Protocol struct {
 	Port string `json:"port"`
 }
 
+// UnmarshalJSON also accepts the transport key used by the GET endpoints in place of type.
+func (p *Protocol) UnmarshalJSON(data []byte) error {
+	type plain Protocol
+	aux := struct {
+		*plain
+		Transport string `json:"transport"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if p.Type == "" {
+		p.Type = aux.Transport
+	}
+	return nil
+}
+
 type PublisherIdentity struct {
 	PublisherID   string `json:"publisher_id"`
 	PublisherName string `json:"publisher_name"`
 }
 
+// UnmarshalJSON also accepts a numeric publisher_id, as returned by the GET endpoints.
+func (p *PublisherIdentity) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		PublisherID   FlexString `json:"publisher_id"`
+		PublisherName string     `json:"publisher_name"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.PublisherID = string(aux.PublisherID)
+	p.PublisherName = aux.PublisherName
+	return nil
+}
+
 type PrivateAppTags struct {
 	TagName string `json:"tag_name"`
 }
 
-func (c *Client) GetPrivateApps() (interface{}, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/steering/apps/private", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
+// privateAppsService implements PrivateAppsService for a Client.
+type privateAppsService struct {
+	c *Client
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+func (s *privateAppsService) List(ctx context.Context) (*PrivateAppsList, error) {
+	list, err := doJSON[PrivateAppsList](ctx, s.c, "GET", "/api/v2/steering/apps/private", nil)
+	if err != nil {
 		return nil, err
 	}
-
-	if res.Status == "success" {
-		return res.Data, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+	return &list, nil
 }
 
-func (c *Client) GetPrivateAppsWithFilter(filter string) (interface{}, error) {
+func (s *privateAppsService) ListWithFilter(ctx context.Context, filter string) (*PrivateAppsList, error) {
 	//Escape Filter
 	filter = url.QueryEscape(filter)
-
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/steering/apps/private?query=%s", c.BaseURL, filter), nil)
+	list, err := doJSON[PrivateAppsList](ctx, s.c, "GET", "/api/v2/steering/apps/private?query="+filter, nil)
 	if err != nil {
 		return nil, err
 	}
+	return &list, nil
+}
 
-	//Debug
-	//reqDump, err := httputil.DumpRequestOut(req, true)
-	//if err != nil {
-	//	log.Fatal(err)
-	//}
-	//fmt.Println(string(reqDump))
+func (s *privateAppsService) Pages(opts ListOptions) *Pager[PrivateAppSummary] {
+	return NewPager(opts, listFetcher(s.c, "/api/v2/steering/apps/private", func(l *PrivateAppsList) []PrivateAppSummary {
+		return l.PrivateApps
+	}))
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+func (s *privateAppsService) Get(ctx context.Context, options PrivateAppOptions) (*PrivateApp, error) {
+	app, err := doJSON[PrivateApp](ctx, s.c, "GET", "/api/v2/steering/apps/private/"+options.Id, nil)
+	if err != nil {
 		return nil, err
 	}
+	return &app, nil
+}
 
-	if res.Status == "success" {
-		return res.Data, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+func (s *privateAppsService) Create(ctx context.Context, privateapp PrivateApp) (*PrivateApp, error) {
+	return verifiedCreate(ctx, s.c, func(ctx context.Context) (*PrivateApp, error) {
+		app, err := doJSON[PrivateApp](ctx, s.c, "POST", "/api/v2/steering/apps/private", privateapp)
+		if err != nil {
+			return nil, err
+		}
+		return &app, nil
+	}, func(ctx context.Context) (*PrivateApp, error) {
+		return s.lookup(ctx, privateapp.AppName)
+	})
 }
 
-func (c *Client) GetPrivateAppId(options PrivateAppOptions) (interface{}, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/steering/apps/private/%s", c.BaseURL, options.Id), nil)
+// lookup returns the private app named name, or nil if there is none.
+func (s *privateAppsService) lookup(ctx context.Context, name string) (*PrivateApp, error) {
+	list, err := s.ListWithFilter(ctx, fmt.Sprintf("app_name eq %q", name))
 	if err != nil {
 		return nil, err
 	}
-
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
-
-	if res.Status == "success" {
-		return res.Data, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
+	for _, app := range list.PrivateApps {
+		if app.AppName == name {
+			return s.Get(ctx, PrivateAppOptions{Id: strconv.Itoa(int(app.AppID))})
+		}
 	}
+	return nil, nil
 }
 
-func (c *Client) CreatePrivateApp(privateapp PrivateApp) (*PrivateApp, error) {
-	//Define JSON Body
-	json_body, err := json.Marshal(privateapp)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
-
-	//Setup the HTTP Request
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/steering/apps/private", c.BaseURL), bytes.NewBuffer(json_body))
+func (s *privateAppsService) Update(ctx context.Context, options PrivateAppOptions, privateapp PrivateApp) (*PrivateApp, error) {
+	app, err := doJSON[PrivateApp](ctx, s.c, "PATCH", "/api/v2/steering/apps/private/"+options.Id, privateapp)
 	if err != nil {
 		return nil, err
 	}
+	return &app, nil
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+func (s *privateAppsService) Replace(ctx context.Context, options PrivateAppOptions, privateapp PrivateApp) (*PrivateApp, error) {
+	app, err := doJSON[PrivateApp](ctx, s.c, "PUT", "/api/v2/steering/apps/private/"+options.Id, privateapp)
+	if err != nil {
 		return nil, err
 	}
+	return &app, nil
+}
 
-	if res.Status == "success" {
-		//return res.Data, nil
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := PrivateApp{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
-
+func (s *privateAppsService) Delete(ctx context.Context, options PrivateAppOptions) error {
+	_, _, err := s.c.do(ctx, "DELETE", "/api/v2/steering/apps/private/"+options.Id, nil)
+	return err
 }
 
-func (c *Client) DeletePrivateApp(options PrivateAppOptions) (*successResponse, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/v2/steering/apps/private/%s", c.BaseURL, options.Id), nil)
-	if err != nil {
-		return nil, err
-	}
+// GetPrivateApps returns the list of private apps of the tenant.
+//
+// Deprecated: Use c.PrivateApps.List.
+func (c *Client) GetPrivateApps() (*PrivateAppsList, error) {
+	return c.PrivateApps.List(context.Background())
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+// GetPrivateAppsWithContext is like GetPrivateApps but uses ctx for the request.
+// It forwards to c.PrivateApps.List.
+func (c *Client) GetPrivateAppsWithContext(ctx context.Context) (*PrivateAppsList, error) {
+	return c.PrivateApps.List(ctx)
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := successResponse{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+// GetPrivateAppsWithFilter returns the list of private apps matching filter.
+//
+// Deprecated: Use c.PrivateApps.ListWithFilter.
+func (c *Client) GetPrivateAppsWithFilter(filter string) (*PrivateAppsList, error) {
+	return c.PrivateApps.ListWithFilter(context.Background(), filter)
 }
 
-func (c *Client) UpdatePrivateApp(options PrivateAppOptions, privateapp PrivateApp) (*PrivateApp, error) {
-	//Define JSON Body
-	json_body, err := json.Marshal(privateapp)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
+// GetPrivateAppsWithFilterWithContext is like GetPrivateAppsWithFilter but uses ctx for the request.
+// It forwards to c.PrivateApps.ListWithFilter.
+func (c *Client) GetPrivateAppsWithFilterWithContext(ctx context.Context, filter string) (*PrivateAppsList, error) {
+	return c.PrivateApps.ListWithFilter(ctx, filter)
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/api/v2/steering/apps/private/%s", c.BaseURL, options.Id), bytes.NewBuffer(json_body))
-	if err != nil {
-		return nil, err
-	}
+// GetPrivateAppId returns the private app identified by options.Id.
+//
+// Deprecated: Use c.PrivateApps.Get.
+func (c *Client) GetPrivateAppId(options PrivateAppOptions) (*PrivateApp, error) {
+	return c.PrivateApps.Get(context.Background(), options)
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+// GetPrivateAppIdWithContext is like GetPrivateAppId but uses ctx for the request.
+// It forwards to c.PrivateApps.Get.
+func (c *Client) GetPrivateAppIdWithContext(ctx context.Context, options PrivateAppOptions) (*PrivateApp, error) {
+	return c.PrivateApps.Get(ctx, options)
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := PrivateApp{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+// Deprecated: Use c.PrivateApps.Create.
+func (c *Client) CreatePrivateApp(privateapp PrivateApp) (*PrivateApp, error) {
+	return c.PrivateApps.Create(context.Background(), privateapp)
+}
 
+// CreatePrivateAppWithContext is like CreatePrivateApp but uses ctx for the request.
+// It forwards to c.PrivateApps.Create.
+func (c *Client) CreatePrivateAppWithContext(ctx context.Context, privateapp PrivateApp) (*PrivateApp, error) {
+	return c.PrivateApps.Create(ctx, privateapp)
 }
 
-func (c *Client) ReplacePrivateApp(options PrivateAppOptions, privateapp PrivateApp) (*PrivateApp, error) {
-	//Define JSON Body
-	json_body, err := json.Marshal(privateapp)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
+// Deprecated: Use c.PrivateApps.Delete.
+func (c *Client) DeletePrivateApp(options PrivateAppOptions) (*successResponse, error) {
+	return c.DeletePrivateAppWithContext(context.Background(), options)
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/v2/steering/apps/private/%s", c.BaseURL, options.Id), bytes.NewBuffer(json_body))
-	if err != nil {
+// DeletePrivateAppWithContext is like DeletePrivateApp but uses ctx for the request.
+// It forwards to c.PrivateApps.Delete.
+func (c *Client) DeletePrivateAppWithContext(ctx context.Context, options PrivateAppOptions) (*successResponse, error) {
+	if err := c.PrivateApps.Delete(ctx, options); err != nil {
 		return nil, err
 	}
+	return &successResponse{Status: "success"}, nil
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+// Deprecated: Use c.PrivateApps.Update.
+func (c *Client) UpdatePrivateApp(options PrivateAppOptions, privateapp PrivateApp) (*PrivateApp, error) {
+	return c.PrivateApps.Update(context.Background(), options, privateapp)
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := PrivateApp{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+// UpdatePrivateAppWithContext is like UpdatePrivateApp but uses ctx for the request.
+// It forwards to c.PrivateApps.Update.
+func (c *Client) UpdatePrivateAppWithContext(ctx context.Context, options PrivateAppOptions, privateapp PrivateApp) (*PrivateApp, error) {
+	return c.PrivateApps.Update(ctx, options, privateapp)
+}
+
+// Deprecated: Use c.PrivateApps.Replace.
+func (c *Client) ReplacePrivateApp(options PrivateAppOptions, privateapp PrivateApp) (*PrivateApp, error) {
+	return c.PrivateApps.Replace(context.Background(), options, privateapp)
+}
 
+// ReplacePrivateAppWithContext is like ReplacePrivateApp but uses ctx for the request.
+// It forwards to c.PrivateApps.Replace.
+func (c *Client) ReplacePrivateAppWithContext(ctx context.Context, options PrivateAppOptions, privateapp PrivateApp) (*PrivateApp, error) {
+	return c.PrivateApps.Replace(ctx, options, privateapp)
 }
@@ -0,0 +1,100 @@
+package nsgo_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestPrivateApps(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+
+	pub := srv.AddPublisher(nsgotest.Publisher{Name: "pub-1", Status: "connected", Registered: true})
+
+	created, err := nsclient.CreatePrivateApp(nsgo.PrivateApp{
+		AppName:          "wiki",
+		Host:             "wiki.internal",
+		Protocols:        []nsgo.Protocol{{Type: "tcp", Port: "443"}},
+		Publishers:       []nsgo.PublisherIdentity{{PublisherID: strconv.Itoa(pub)}},
+		Tags:             []nsgo.PrivateAppTags{{TagName: "docs"}},
+		ClientlessAccess: nsgo.Bool(false),
+	})
+	if err != nil {
+		t.Fatalf("CreatePrivateApp: %v", err)
+	}
+	if created.Id == 0 || created.AppName != "wiki" || len(created.Publishers) != 1 || created.Publishers[0].PublisherName != "pub-1" {
+		t.Fatalf("CreatePrivateApp = %+v", created)
+	}
+	id := strconv.Itoa(created.Id)
+
+	got, err := nsclient.GetPrivateAppId(nsgo.PrivateAppOptions{Id: id})
+	if err != nil {
+		t.Fatalf("GetPrivateAppId: %v", err)
+	}
+	if got.Id != created.Id || len(got.Protocols) != 1 || got.Protocols[0].Type != "tcp" || got.Protocols[0].Port != "443" {
+		t.Errorf("GetPrivateAppId = %+v", got)
+	}
+	if got.Reachability == nil || bool(got.Reachability.Reachable) || got.Reachability.ErrorCode != 1001 {
+		t.Errorf("GetPrivateAppId reachability = %+v", got.Reachability)
+	}
+
+	srv.AddPrivateApp(nsgotest.PrivateApp{Name: "git", Host: "git.internal", Reachable: true})
+	list, err := nsclient.GetPrivateApps()
+	if err != nil {
+		t.Fatalf("GetPrivateApps: %v", err)
+	}
+	if len(list.PrivateApps) != 2 {
+		t.Fatalf("GetPrivateApps returned %d apps, want 2", len(list.PrivateApps))
+	}
+	if protocols := list.PrivateApps[0].Protocols; len(protocols) != 1 || protocols[0].CreatedAt.IsZero() {
+		t.Errorf("GetPrivateApps protocols = %+v, want their timestamps decoded", protocols)
+	}
+	filtered, err := nsclient.GetPrivateAppsWithFilter(`app_name eq "git"`)
+	if err != nil {
+		t.Fatalf("GetPrivateAppsWithFilter: %v", err)
+	}
+	if len(filtered.PrivateApps) != 1 || filtered.PrivateApps[0].AppName != "git" {
+		t.Errorf("GetPrivateAppsWithFilter = %+v", filtered.PrivateApps)
+	}
+
+	// A partial update leaves the fields it does not set alone.
+	updated, err := nsclient.UpdatePrivateApp(nsgo.PrivateAppOptions{Id: id}, nsgo.PrivateApp{UsePublisherDNS: nsgo.Bool(true)})
+	if err != nil {
+		t.Fatalf("UpdatePrivateApp: %v", err)
+	}
+	if updated.UsePublisherDNS == nil || !*updated.UsePublisherDNS || updated.AppName != "wiki" || updated.Host != "wiki.internal" || len(updated.Protocols) != 1 {
+		t.Errorf("UpdatePrivateApp = %+v", updated)
+	}
+	if reqs := srv.Requests(); string(reqs[len(reqs)-1].Body) != `{"use_publisher_dns":true}` {
+		t.Errorf("UpdatePrivateApp sent %s, want only use_publisher_dns", reqs[len(reqs)-1].Body)
+	}
+
+	// The read only fields of an app that was read are not sent back.
+	if _, err := nsclient.UpdatePrivateApp(nsgo.PrivateAppOptions{Id: id}, *got); err != nil {
+		t.Fatalf("UpdatePrivateApp with a read app: %v", err)
+	}
+	if reqs := srv.Requests(); strings.Contains(string(reqs[len(reqs)-1].Body), "reachability") ||
+		strings.Contains(string(reqs[len(reqs)-1].Body), "service_publisher_assignments") {
+		t.Errorf("UpdatePrivateApp sent %s, want no read only fields", reqs[len(reqs)-1].Body)
+	}
+
+	replaced, err := nsclient.ReplacePrivateApp(nsgo.PrivateAppOptions{Id: id}, nsgo.PrivateApp{AppName: "wiki", Host: "wiki2.internal"})
+	if err != nil {
+		t.Fatalf("ReplacePrivateApp: %v", err)
+	}
+	if replaced.Host != "wiki2.internal" || len(replaced.Publishers) != 0 {
+		t.Errorf("ReplacePrivateApp = %+v", replaced)
+	}
+
+	if _, err := nsclient.DeletePrivateApp(nsgo.PrivateAppOptions{Id: id}); err != nil {
+		t.Fatalf("DeletePrivateApp: %v", err)
+	}
+	if _, ok := srv.PrivateApp(created.Id); ok {
+		t.Error("private app still stored after DeletePrivateApp")
+	}
+}
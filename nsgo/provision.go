@@ -0,0 +1,176 @@
+package nsgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrProvisionTimeout is returned by Client.ProvisionPublisher when the publisher did not register
+// and connect in time.
+var ErrProvisionTimeout = errors.New("nsgo: publisher provisioning timed out")
+
+// PublisherStatusConnected is the status of a publisher connected to the tenant.
+const PublisherStatusConnected = "connected"
+
+// ProvisionOptions configures Client.ProvisionPublisher.
+type ProvisionOptions struct {
+	// Publisher is the publisher to create, or to reuse when one has the same name.
+	Publisher PublisherOptions
+	// Deploy deploys the publisher with its registration token, i.e. by starting a VM with it.
+	// It is not called for a reused publisher that is already registered.
+	Deploy func(ctx context.Context, publisher *Publisher, token string) error
+	// PollMin and PollMax bound the wait between two checks of the publisher, which doubles
+	// from PollMin. They default to 5s and 1m.
+	PollMin time.Duration
+	PollMax time.Duration
+	// Timeout limits the wait for the publisher to register and connect once deployed.
+	// It defaults to 10m.
+	Timeout time.Duration
+}
+
+// ProvisionStep is a step of Client.ProvisionPublisher.
+type ProvisionStep string
+
+const (
+	ProvisionCreated    ProvisionStep = "created"
+	ProvisionReused     ProvisionStep = "reused"
+	ProvisionToken      ProvisionStep = "token"
+	ProvisionDeployed   ProvisionStep = "deployed"
+	ProvisionStatus     ProvisionStep = "status" // the status of the publisher changed while waiting
+	ProvisionRegistered ProvisionStep = "registered"
+	ProvisionConnected  ProvisionStep = "connected"
+)
+
+// ProvisionEvent is a step of Client.ProvisionPublisher, with the status the publisher had then.
+type ProvisionEvent struct {
+	Step   ProvisionStep
+	At     time.Time
+	Status string
+}
+
+// ProvisionResult is the outcome of Client.ProvisionPublisher.
+type ProvisionResult struct {
+	// Publisher is the publisher as last read.
+	Publisher *Publisher
+	// Timeline lists the steps taken, in order.
+	Timeline []ProvisionEvent
+}
+
+// ProvisionPublisher creates the publisher set by opts, or reuses the one with the same name,
+// mints a registration token and hands it to opts.Deploy, then waits for the publisher to register
+// and connect. The registration token is neither logged nor part of the result.
+//
+// On failure, the result is returned along with the error, with the steps taken so far. When the
+// publisher does not connect within opts.Timeout, the error wraps ErrProvisionTimeout.
+//
+//	res, err := nsclient.ProvisionPublisher(ctx, nsgo.ProvisionOptions{
+//		Publisher: nsgo.PublisherOptions{Name: "branch-1"},
+//		Deploy: func(ctx context.Context, p *nsgo.Publisher, token string) error {
+//			return startPublisherVM(ctx, p.Name, token)
+//		},
+//	})
+func (c *Client) ProvisionPublisher(ctx context.Context, opts ProvisionOptions) (*ProvisionResult, error) {
+	if opts.Deploy == nil {
+		return nil, errors.New("nsgo: no deploy function")
+	}
+	if opts.PollMin <= 0 {
+		opts.PollMin = 5 * time.Second
+	}
+	if opts.PollMax < opts.PollMin {
+		opts.PollMax = max(time.Minute, opts.PollMin)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Minute
+	}
+	res := &ProvisionResult{}
+	record := func(step ProvisionStep) {
+		res.Timeline = append(res.Timeline, ProvisionEvent{Step: step, At: time.Now(), Status: res.Publisher.Status})
+	}
+	name := opts.Publisher.Name
+
+	var err error
+	res.Publisher, err = findPublisher(ctx, c.Publishers, name)
+	if err != nil {
+		return res, err
+	}
+	if res.Publisher == nil {
+		res.Publisher, err = c.Publishers.Create(ctx, opts.Publisher)
+		if errors.Is(err, ErrConflict) {
+			// Created concurrently by someone else.
+			res.Publisher, err = findPublisher(ctx, c.Publishers, name)
+			if err == nil && res.Publisher == nil {
+				err = fmt.Errorf("nsgo: publisher %s exists but cannot be found", name)
+			}
+		}
+		if err != nil {
+			return res, err
+		}
+		record(ProvisionCreated)
+	} else {
+		record(ProvisionReused)
+	}
+	id := PublisherOptions{Id: strconv.Itoa(res.Publisher.ID)}
+
+	if !res.Publisher.Registered {
+		token, err := c.Publishers.Token(ctx, id)
+		if err != nil {
+			return res, err
+		}
+		record(ProvisionToken)
+		if err := opts.Deploy(ctx, res.Publisher, token.Token); err != nil {
+			return res, fmt.Errorf("nsgo: deploying publisher %s: %w", name, err)
+		}
+		record(ProvisionDeployed)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	registered := false
+	status := res.Publisher.Status
+	for wait := opts.PollMin; ; wait = min(2*wait, opts.PollMax) {
+		p := res.Publisher
+		if p.Status != status {
+			status = p.Status
+			record(ProvisionStatus)
+		}
+		if p.Registered && !registered {
+			registered = true
+			record(ProvisionRegistered)
+		}
+		if p.Registered && p.Status == PublisherStatusConnected {
+			record(ProvisionConnected)
+			return res, nil
+		}
+
+		err := sleep(waitCtx, wait)
+		if err == nil {
+			var latest *Publisher
+			if latest, err = c.Publishers.Get(waitCtx, id); err == nil {
+				res.Publisher = latest
+			}
+		}
+		if err != nil {
+			if ctx.Err() == nil && waitCtx.Err() != nil {
+				return res, fmt.Errorf("%w: publisher %s is %q after %v", ErrProvisionTimeout, name, res.Publisher.Status, opts.Timeout)
+			}
+			return res, err
+		}
+	}
+}
+
+// findPublisher returns the publisher of svc named name, or nil if there is none.
+func findPublisher(ctx context.Context, svc PublishersService, name string) (*Publisher, error) {
+	list, err := svc.ListWithFilter(ctx, fmt.Sprintf("publisher_name eq %q", name))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range list.Publishers {
+		if p.PublisherName == name {
+			return svc.Get(ctx, PublisherOptions{Id: strconv.Itoa(int(p.PublisherID))})
+		}
+	}
+	return nil, nil
+}
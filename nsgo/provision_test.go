@@ -0,0 +1,118 @@
+package nsgo_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+// provisionSteps returns the steps of res, leaving out status changes.
+func provisionSteps(res *nsgo.ProvisionResult) []nsgo.ProvisionStep {
+	var steps []nsgo.ProvisionStep
+	for _, e := range res.Timeline {
+		if e.Step != nsgo.ProvisionStatus {
+			steps = append(steps, e.Step)
+		}
+	}
+	return steps
+}
+
+func TestProvisionPublisher(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client(nsgo.WithoutRateLimit())
+
+	res, err := nsclient.ProvisionPublisher(context.Background(), nsgo.ProvisionOptions{
+		Publisher: nsgo.PublisherOptions{Name: "pub-1"},
+		Deploy: func(ctx context.Context, p *nsgo.Publisher, token string) error {
+			if token == "" {
+				t.Error("Deploy: empty token")
+			}
+			srv.UpdatePublisher(p.ID, func(p *nsgotest.Publisher) {
+				p.Registered = true
+				p.Status = "not connected"
+			})
+			time.AfterFunc(20*time.Millisecond, func() {
+				srv.UpdatePublisher(p.ID, func(p *nsgotest.Publisher) { p.Status = "connected" })
+			})
+			return nil
+		},
+		PollMin: time.Millisecond,
+		PollMax: 5 * time.Millisecond,
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("ProvisionPublisher: %v", err)
+	}
+	if res.Publisher.Name != "pub-1" || res.Publisher.Status != nsgo.PublisherStatusConnected {
+		t.Errorf("Publisher = %+v", res.Publisher)
+	}
+	want := []nsgo.ProvisionStep{nsgo.ProvisionCreated, nsgo.ProvisionToken, nsgo.ProvisionDeployed, nsgo.ProvisionRegistered, nsgo.ProvisionConnected}
+	if got := provisionSteps(res); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+
+	// Provisioning again reuses the connected publisher, without deploying it.
+	res, err = nsclient.ProvisionPublisher(context.Background(), nsgo.ProvisionOptions{
+		Publisher: nsgo.PublisherOptions{Name: "pub-1"},
+		Deploy: func(ctx context.Context, p *nsgo.Publisher, token string) error {
+			t.Error("Deploy called for a registered publisher")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("ProvisionPublisher again: %v", err)
+	}
+	want = []nsgo.ProvisionStep{nsgo.ProvisionReused, nsgo.ProvisionRegistered, nsgo.ProvisionConnected}
+	if got := provisionSteps(res); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+	tokens := 0
+	for _, r := range srv.Requests() {
+		if strings.HasSuffix(r.Path, "/registration_token") {
+			tokens++
+		}
+	}
+	if tokens != 1 {
+		t.Errorf("%d registration tokens minted, want 1", tokens)
+	}
+}
+
+func TestProvisionPublisherTimeout(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client(nsgo.WithoutRateLimit())
+
+	opts := nsgo.ProvisionOptions{
+		Publisher: nsgo.PublisherOptions{Name: "pub-1"},
+		Deploy: func(ctx context.Context, p *nsgo.Publisher, token string) error {
+			return nil
+		},
+		PollMin: time.Millisecond,
+		PollMax: 5 * time.Millisecond,
+		Timeout: 30 * time.Millisecond,
+	}
+	res, err := nsclient.ProvisionPublisher(context.Background(), opts)
+	if !errors.Is(err, nsgo.ErrProvisionTimeout) {
+		t.Fatalf("ProvisionPublisher: err = %v, want ErrProvisionTimeout", err)
+	}
+	want := []nsgo.ProvisionStep{nsgo.ProvisionCreated, nsgo.ProvisionToken, nsgo.ProvisionDeployed}
+	if got := provisionSteps(res); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+
+	// A failed deployment stops the workflow.
+	deployErr := errors.New("no capacity")
+	opts.Deploy = func(ctx context.Context, p *nsgo.Publisher, token string) error {
+		return deployErr
+	}
+	if _, err := nsclient.ProvisionPublisher(context.Background(), opts); !errors.Is(err, deployErr) {
+		t.Errorf("ProvisionPublisher: err = %v, want %v", err, deployErr)
+	}
+}
@@ -0,0 +1,51 @@
+package nsgo
+
+import "context"
+
+// PublisherAlertEvent is a publisher event the tenant can email admins about.
+type PublisherAlertEvent string
+
+const (
+	PublisherAlertUpgradeWillStart PublisherAlertEvent = "UPGRADE_WILL_START"
+	PublisherAlertUpgradeStarted   PublisherAlertEvent = "UPGRADE_STARTED"
+	PublisherAlertUpgradeSucceeded PublisherAlertEvent = "UPGRADE_SUCCEEDED"
+	PublisherAlertUpgradeFailed    PublisherAlertEvent = "UPGRADE_FAILED"
+	PublisherAlertConnectionFailed PublisherAlertEvent = "CONNECTION_FAILED"
+)
+
+// PublisherAlertsConfiguration sets which publisher events are emailed, and to whom.
+//
+//	config := nsgo.PublisherAlertsConfiguration{
+//		AdminUsers: []string{"noc@example.com"},
+//		EventTypes: []nsgo.PublisherAlertEvent{nsgo.PublisherAlertConnectionFailed, nsgo.PublisherAlertUpgradeFailed},
+//	}
+type PublisherAlertsConfiguration struct {
+	// AdminUsers are the email addresses of the admins receiving the alerts.
+	AdminUsers []string `json:"adminUsers"`
+	// EventTypes are the events alerts are sent for.
+	EventTypes []PublisherAlertEvent `json:"eventTypes"`
+	// SelectedUsers is the group of admins, if any, the alerts are sent to.
+	SelectedUsers string `json:"selectedUsers,omitempty"`
+}
+
+func (s *publishersService) AlertsConfiguration(ctx context.Context) (*PublisherAlertsConfiguration, error) {
+	config, err := doJSON[PublisherAlertsConfiguration](ctx, s.c, "GET", "/api/v2/infrastructure/publishers/alertsconfiguration", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (s *publishersService) UpdateAlertsConfiguration(ctx context.Context, config PublisherAlertsConfiguration) (*PublisherAlertsConfiguration, error) {
+	if config.AdminUsers == nil {
+		config.AdminUsers = []string{}
+	}
+	if config.EventTypes == nil {
+		config.EventTypes = []PublisherAlertEvent{}
+	}
+	updated, err := doJSON[PublisherAlertsConfiguration](ctx, s.c, "PUT", "/api/v2/infrastructure/publishers/alertsconfiguration", config)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
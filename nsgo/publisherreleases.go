@@ -0,0 +1,53 @@
+package nsgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownRelease is returned by ValidateDockerTag for a docker tag that is not one of the
+// publisher releases of the tenant.
+var ErrUnknownRelease = errors.New("nsgo: unknown publisher release")
+
+// PublisherRelease is a publisher release the tenant can upgrade publishers to.
+// PublisherCount is the number of publishers running it.
+type PublisherRelease struct {
+	Name           string  `json:"name"`
+	Version        string  `json:"version"`
+	DockerTag      string  `json:"docker_tag"`
+	ReleaseType    string  `json:"release_type"`
+	PublisherCount FlexInt `json:"publisher_count"`
+}
+
+func (s *publishersService) Releases(ctx context.Context) ([]PublisherRelease, error) {
+	return doJSON[[]PublisherRelease](ctx, s.c, "GET", "/api/v2/infrastructure/publishers/releases", nil)
+}
+
+// GetPublisherReleases returns the publisher releases the tenant can upgrade publishers to.
+// It forwards to c.Publishers.Releases.
+func (c *Client) GetPublisherReleases(ctx context.Context) ([]PublisherRelease, error) {
+	return c.Publishers.Releases(ctx)
+}
+
+// ValidateDockerTag returns an error wrapping ErrUnknownRelease when tag is not the docker tag of
+// one of releases, i.e. before creating a publisher upgrade profile with it.
+//
+//	releases, err := nsclient.Publishers.Releases(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	if err := nsgo.ValidateDockerTag(releases, options.DockerTag); err != nil {
+//		return err
+//	}
+func ValidateDockerTag(releases []PublisherRelease, tag string) error {
+	tags := make([]string, len(releases))
+	for i, r := range releases {
+		if r.DockerTag == tag {
+			return nil
+		}
+		tags[i] = r.DockerTag
+	}
+	return fmt.Errorf("%w: docker tag %q is not one of %s", ErrUnknownRelease, tag, strings.Join(tags, ", "))
+}
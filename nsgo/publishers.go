@@ -1,47 +1,33 @@
 package nsgo
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
+	"context"
 	"net/url"
+	"strconv"
 )
 
 // PublisherList struct is used to define a list of Netskope publishers returned from the GET method.
 
 type PublishersList struct {
-	Publishers []struct {
-		Assessment struct {
-			EeeSupport string `json:"eee_support"`
-			HddFree    string `json:"hdd_free"`
-			HddTotal   string `json:"hdd_total"`
-			IPAddress  string `json:"ip_address"`
-			Latency    string `json:"latency"`
-			Version    string `json:"version"`
-		} `json:"assessment"`
-		CommonName                         string   `json:"common_name"`
-		Lbrokerconnect                     bool     `json:"lbrokerconnect"`
-		PublisherID                        int      `json:"publisher_id"`
-		PublisherName                      string   `json:"publisher_name"`
-		PublisherUpgradeProfilesExternalID int      `json:"publisher_upgrade_profiles_external_id"`
-		Registered                         bool     `json:"registered"`
-		Status                             string   `json:"status"`
-		StitcherID                         int      `json:"stitcher_id"`
-		Tags                               []string `json:"tags"`
-		UpgradeFailedReason                struct {
-			Detail    string `json:"detail"`
-			ErrorCode string `json:"error_code"`
-			Timestamp string `json:"timestamp"`
-			Version   string `json:"version"`
-		} `json:"upgrade_failed_reason"`
-		UpgradeRequest bool `json:"upgrade_request"`
-		UpgradeStatus  struct {
-			StatusFailureCode string `json:"status_failure_code"`
-			Upstat            string `json:"upstat"`
-		} `json:"upgrade_status,omitempty"`
-	} `json:"publishers"`
+	Publishers []PublisherSummary `json:"publishers"`
+}
+
+// PublisherSummary is an individual publisher as returned in a PublishersList.
+// The API is not consistent in the JSON types it uses for these fields, hence the Flex types.
+type PublisherSummary struct {
+	Assessment                         Assessment                   `json:"assessment"`
+	CommonName                         string                       `json:"common_name"`
+	Lbrokerconnect                     FlexBool                     `json:"lbrokerconnect"`
+	PublisherID                        FlexInt                      `json:"publisher_id"`
+	PublisherName                      string                       `json:"publisher_name"`
+	PublisherUpgradeProfilesExternalID FlexInt                      `json:"publisher_upgrade_profiles_external_id"`
+	Registered                         FlexBool                     `json:"registered"`
+	Status                             string                       `json:"status"`
+	StitcherID                         FlexInt                      `json:"stitcher_id"`
+	Tags                               []string                     `json:"tags"`
+	UpgradeFailedReason                PublisherUpgradeFailedReason `json:"upgrade_failed_reason"`
+	UpgradeRequest                     FlexBool                     `json:"upgrade_request"`
+	UpgradeStatus                      PublisherUpgradeStatus       `json:"upgrade_status,omitempty"`
 }
 
 // Publisher is a struct used to define and individual Netskope Publisher.
@@ -56,14 +42,46 @@ type Publisher struct {
 	StitcherID int        `json:"stitcher_id"`
 }
 
-// Assessment is a struct used inside of the Publisher struct.
-// BUG(terraform-provider-netskope): Need tp modify EeeSupport so it isn't returned in JSON.
+// Assessment is a struct used inside of the Publisher and PublisherSummary structs.
+// EeeSupport is sent as a bool for a single publisher but as a string in lists, hence FlexBool.
 type Assessment struct {
-	EeeSupport bool   `json:"eee_support"`
-	HddFree    string `json:"hdd_free"`
-	HddTotal   string `json:"hdd_total"`
-	IPAddress  string `json:"ip_address"`
-	Version    string `json:"version"`
+	EeeSupport FlexBool   `json:"eee_support"`
+	HddFree    FlexString `json:"hdd_free"`
+	HddTotal   FlexString `json:"hdd_total"`
+	IPAddress  string     `json:"ip_address"`
+	Latency    FlexString `json:"latency,omitempty"`
+	Version    string     `json:"version"`
+}
+
+// PublisherUpgradeFailedReason describes why the last upgrade of a publisher failed, if it did.
+type PublisherUpgradeFailedReason struct {
+	Detail    string     `json:"detail"`
+	ErrorCode FlexString `json:"error_code"`
+	Timestamp FlexString `json:"timestamp"`
+	Version   string     `json:"version"`
+}
+
+// PublisherUpgradeStatus reports the progress of the upgrade of a publisher.
+type PublisherUpgradeStatus struct {
+	StatusFailureCode FlexString `json:"status_failure_code"`
+	Upstat            string     `json:"upstat"`
+}
+
+// The API sends null, "" or {} for an assessment or upgrade state it has no data for.
+
+func (a *Assessment) UnmarshalJSON(data []byte) error {
+	type plain Assessment
+	return decodeObject(data, (*plain)(a))
+}
+
+func (r *PublisherUpgradeFailedReason) UnmarshalJSON(data []byte) error {
+	type plain PublisherUpgradeFailedReason
+	return decodeObject(data, (*plain)(r))
+}
+
+func (s *PublisherUpgradeStatus) UnmarshalJSON(data []byte) error {
+	type plain PublisherUpgradeStatus
+	return decodeObject(data, (*plain)(s))
 }
 
 // PublisherOptions struct defines details used in GET by ID, Create, Update and Delete methods.
@@ -77,287 +95,236 @@ type Assessment struct {
 //	}
 //
 //	updatepublisher := nsgo.PublisherOptions{
-//		Id: "987",
+//		Id:             "987",
+//		Lbrokerconnect: nsgo.Bool(false),
 //	}
+//
+// Fields left unset are not sent, so UpdatePublisher only changes the fields that were set.
 type PublisherOptions struct {
 	Name                       string `json:"name,omitempty"`
 	Id                         string `json:"id,omitempty"`
-	Lbrokerconnect             bool   `json:"lbrokerconnect,omitempty"`
+	Lbrokerconnect             *bool  `json:"lbrokerconnect,omitempty"`
 	PublisherUpgradeProfilesID int    `json:"publisher_upgrade_profiles_id,omitempty"`
 	Tags                       []struct {
 		TagName string `json:"tag_name"`
 	} `json:"tags,omitempty"`
 }
 
+// PublisherApp is a private app served by a publisher, as returned by PublishersService.Apps.
+// Primary reports whether the publisher is the primary one of the app, and Reachability whether
+// the publisher reaches the app.
+type PublisherApp struct {
+	ID               FlexInt                `json:"app_id"`
+	Name             string                 `json:"app_name"`
+	Host             string                 `json:"host"`
+	ClientlessAccess FlexBool               `json:"clientless_access"`
+	Protocols        []Protocol             `json:"protocols"`
+	Primary          FlexBool               `json:"primary"`
+	Reachability     PrivateAppReachability `json:"reachability"`
+}
+
 // PublisherToken struct is used to define the token response data.
 type PublisherToken struct {
 	Token string `json:"token"`
 }
 
-// GetPublishers function is used to build API request which is sent to sendRequest().
-// It is called using the client struct, and returns an interface with a list of Publishers.
-// The interface can be marshaled into the PublishersList struct.
-//
-// BUG(terraform-provider-netskope): Need tp modify the Assessment struct so that this request can return a PublishersList struct instead of an interface.
-func (c *Client) GetPublishers() (interface{}, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/infrastructure/publishers", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
+// publishersService implements PublishersService for a Client.
+type publishersService struct {
+	c *Client
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+func (s *publishersService) List(ctx context.Context) (*PublishersList, error) {
+	list, err := doJSON[PublishersList](ctx, s.c, "GET", "/api/v2/infrastructure/publishers", nil)
+	if err != nil {
 		return nil, err
 	}
-
-	if res.Status == "success" {
-		/*
-			jsonData, err := json.Marshal(res.Data)
-			if err != nil {
-				return nil, err
-			}
-			dataStruct := PublishersList{}
-			json.Unmarshal(jsonData, &dataStruct)
-			return &dataStruct, nil
-		*/
-		return res.Data, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+	return &list, nil
 }
 
-// GetPublishersWithFilters function is used to build API request which is sent to sendRequest().
-// It is called using the client struct and the NpaFilters Struct. It returns an interface with a list of Filtered Publishers.
-// The interface can be marshaled into the PublishersList struct.
-//
-// BUG(terraform-provider-netskope): Need tp modify the Assessment struct so that this request can return a PublishersList struct instead of an interface.
-func (c *Client) GetPublishersWithFilter(filter string) (interface{}, error) {
+func (s *publishersService) ListWithFilter(ctx context.Context, filter string) (*PublishersList, error) {
 	//Escape Filter
 	filter = url.QueryEscape(filter)
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/infrastructure/publishers?query=%s", c.BaseURL, filter), nil)
+	list, err := doJSON[PublishersList](ctx, s.c, "GET", "/api/v2/infrastructure/publishers?query="+filter, nil)
 	if err != nil {
 		return nil, err
 	}
+	return &list, nil
+}
 
-	//Debug
-	//reqDump, err := httputil.DumpRequestOut(req, true)
-	//if err != nil {
-	//	log.Fatal(err)
-	//}
-	//fmt.Println(string(reqDump))
-
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
-
-	if res.Status == "success" {
-		/*
-			jsonData, err := json.Marshal(res.Data)
-			if err != nil {
-				return nil, err
-			}
-			dataStruct := PublishersList{}
-			json.Unmarshal(jsonData, &dataStruct)
-			return &dataStruct, nil
-		*/
-		return res.Data, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+func (s *publishersService) Pages(opts ListOptions) *Pager[PublisherSummary] {
+	return NewPager(opts, listFetcher(s.c, "/api/v2/infrastructure/publishers", func(l *PublishersList) []PublisherSummary {
+		return l.Publishers
+	}))
 }
 
-// GetPublisherId function is used to build API request which is sent to sendRequest().
-// It is called using the client struct, takes and returns an interface.
-func (c *Client) GetPublisherId(options PublisherOptions) (*Publisher, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/infrastructure/publishers/%s", c.BaseURL, options.Id), nil)
+func (s *publishersService) Get(ctx context.Context, options PublisherOptions) (*Publisher, error) {
+	publisher, err := doJSON[Publisher](ctx, s.c, "GET", "/api/v2/infrastructure/publishers/"+options.Id, nil)
 	if err != nil {
 		return nil, err
 	}
+	return &publisher, nil
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
-
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
+func (s *publishersService) Create(ctx context.Context, options PublisherOptions) (*Publisher, error) {
+	return verifiedCreate(ctx, s.c, func(ctx context.Context) (*Publisher, error) {
+		publisher, err := doJSON[Publisher](ctx, s.c, "POST", "/api/v2/infrastructure/publishers", options)
 		if err != nil {
 			return nil, err
 		}
-		dataStruct := Publisher{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+		return &publisher, nil
+	}, func(ctx context.Context) (*Publisher, error) {
+		return s.lookup(ctx, options.Name)
+	})
 }
 
-func (c *Client) CreatePublisher(options PublisherOptions) (*Publisher, error) {
-	//Define JSON Body
-	json_body, err := json.Marshal(options)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
+// lookup returns the publisher named name, or nil if there is none.
+func (s *publishersService) lookup(ctx context.Context, name string) (*Publisher, error) {
+	return findPublisher(ctx, s, name)
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/infrastructure/publishers", c.BaseURL), bytes.NewBuffer(json_body))
+func (s *publishersService) Update(ctx context.Context, options PublisherOptions) (*Publisher, error) {
+	publisher, err := doJSON[Publisher](ctx, s.c, "PATCH", "/api/v2/infrastructure/publishers/"+options.Id, options)
 	if err != nil {
 		return nil, err
 	}
+	return &publisher, nil
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+func (s *publishersService) Replace(ctx context.Context, options PublisherOptions) (*Publisher, error) {
+	publisher, err := doJSON[Publisher](ctx, s.c, "PUT", "/api/v2/infrastructure/publishers/"+options.Id, options)
+	if err != nil {
 		return nil, err
 	}
+	return &publisher, nil
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := Publisher{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
-
+func (s *publishersService) Apps(ctx context.Context, publisherID int) ([]PublisherApp, error) {
+	return doJSON[[]PublisherApp](ctx, s.c, "GET", "/api/v2/infrastructure/publishers/"+strconv.Itoa(publisherID)+"/apps", nil)
 }
 
-func (c *Client) GetToken(options PublisherOptions) (*PublisherToken, error) {
-	//Define JSON Body
+func (s *publishersService) Delete(ctx context.Context, options PublisherOptions) error {
+	_, _, err := s.c.do(ctx, "DELETE", "/api/v2/infrastructure/publishers/"+options.Id, nil)
+	return err
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/infrastructure/publishers/%s/registration_token", c.BaseURL, options.Id), nil)
+func (s *publishersService) Token(ctx context.Context, options PublisherOptions) (*PublisherToken, error) {
+	token, err := doJSON[PublisherToken](ctx, s.c, "POST", "/api/v2/infrastructure/publishers/"+options.Id+"/registration_token", nil)
 	if err != nil {
 		return nil, err
 	}
+	return &token, nil
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+// GetPublishers function is used to build API request which is sent to sendRequest().
+// It is called using the client struct, and returns the list of Publishers.
+//
+// Deprecated: Use c.Publishers.List.
+func (c *Client) GetPublishers() (*PublishersList, error) {
+	return c.Publishers.List(context.Background())
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := PublisherToken{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+// GetPublishersWithContext is like GetPublishers but uses ctx for the request.
+// It forwards to c.Publishers.List.
+func (c *Client) GetPublishersWithContext(ctx context.Context) (*PublishersList, error) {
+	return c.Publishers.List(ctx)
+}
 
+// GetPublishersWithFilters function is used to build API request which is sent to sendRequest().
+// It is called using the client struct and a filter query. It returns the list of Filtered Publishers.
+//
+// Deprecated: Use c.Publishers.ListWithFilter.
+func (c *Client) GetPublishersWithFilter(filter string) (*PublishersList, error) {
+	return c.Publishers.ListWithFilter(context.Background(), filter)
 }
 
-func (c *Client) DeletePublisher(options PublisherOptions) (*successResponse, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/v2/infrastructure/publishers/%s", c.BaseURL, options.Id), nil)
-	if err != nil {
-		return nil, err
-	}
+// GetPublishersWithFilterWithContext is like GetPublishersWithFilter but uses ctx for the request.
+// It forwards to c.Publishers.ListWithFilter.
+func (c *Client) GetPublishersWithFilterWithContext(ctx context.Context, filter string) (*PublishersList, error) {
+	return c.Publishers.ListWithFilter(ctx, filter)
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+// GetPublisherId function is used to build API request which is sent to sendRequest().
+// It is called using the client struct, takes and returns an interface.
+//
+// Deprecated: Use c.Publishers.Get.
+func (c *Client) GetPublisherId(options PublisherOptions) (*Publisher, error) {
+	return c.Publishers.Get(context.Background(), options)
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := successResponse{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+// GetPublisherIdWithContext is like GetPublisherId but uses ctx for the request.
+// It forwards to c.Publishers.Get.
+func (c *Client) GetPublisherIdWithContext(ctx context.Context, options PublisherOptions) (*Publisher, error) {
+	return c.Publishers.Get(ctx, options)
 }
 
-func (c *Client) UpdatePublisher(options PublisherOptions) (interface{}, error) {
-	//Define JSON Body
-	json_body, err := json.Marshal(options)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
+// Deprecated: Use c.Publishers.Create.
+func (c *Client) CreatePublisher(options PublisherOptions) (*Publisher, error) {
+	return c.Publishers.Create(context.Background(), options)
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/api/v2/infrastructure/publishers/%s", c.BaseURL, options.Id), bytes.NewBuffer(json_body))
-	if err != nil {
-		return nil, err
-	}
+// CreatePublisherWithContext is like CreatePublisher but uses ctx for the request.
+// It forwards to c.Publishers.Create.
+func (c *Client) CreatePublisherWithContext(ctx context.Context, options PublisherOptions) (*Publisher, error) {
+	return c.Publishers.Create(ctx, options)
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+// Deprecated: Use c.Publishers.Token.
+func (c *Client) GetToken(options PublisherOptions) (*PublisherToken, error) {
+	return c.Publishers.Token(context.Background(), options)
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := Publisher{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+// GetTokenWithContext is like GetToken but uses ctx for the request.
+// It forwards to c.Publishers.Token.
+func (c *Client) GetTokenWithContext(ctx context.Context, options PublisherOptions) (*PublisherToken, error) {
+	return c.Publishers.Token(ctx, options)
+}
 
+// Deprecated: Use c.Publishers.Delete.
+func (c *Client) DeletePublisher(options PublisherOptions) (*successResponse, error) {
+	return c.DeletePublisherWithContext(context.Background(), options)
 }
 
-func (c *Client) ReplacePublisher(options PublisherOptions) (interface{}, error) {
-	//Define JSON Body
-	json_body, err := json.Marshal(options)
-	if err != nil {
-		return nil, errors.New("bad json options")
+// DeletePublisherWithContext is like DeletePublisher but uses ctx for the request.
+// It forwards to c.Publishers.Delete.
+func (c *Client) DeletePublisherWithContext(ctx context.Context, options PublisherOptions) (*successResponse, error) {
+	if err := c.Publishers.Delete(ctx, options); err != nil {
+		return nil, err
 	}
+	return &successResponse{Status: "success"}, nil
+}
+
+// Deprecated: Use c.Publishers.Update.
+func (c *Client) UpdatePublisher(options PublisherOptions) (interface{}, error) {
+	return c.UpdatePublisherWithContext(context.Background(), options)
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/v2/infrastructure/publishers/%s", c.BaseURL, options.Id), bytes.NewBuffer(json_body))
+// UpdatePublisherWithContext is like UpdatePublisher but uses ctx for the request.
+// It forwards to c.Publishers.Update.
+func (c *Client) UpdatePublisherWithContext(ctx context.Context, options PublisherOptions) (interface{}, error) {
+	publisher, err := c.Publishers.Update(ctx, options)
 	if err != nil {
 		return nil, err
 	}
+	return publisher, nil
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+// Deprecated: Use c.Publishers.Replace.
+func (c *Client) ReplacePublisher(options PublisherOptions) (interface{}, error) {
+	return c.ReplacePublisherWithContext(context.Background(), options)
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := Publisher{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
+// ReplacePublisherWithContext is like ReplacePublisher but uses ctx for the request.
+// It forwards to c.Publishers.Replace.
+func (c *Client) ReplacePublisherWithContext(ctx context.Context, options PublisherOptions) (interface{}, error) {
+	publisher, err := c.Publishers.Replace(ctx, options)
+	if err != nil {
+		return nil, err
 	}
+	return publisher, nil
+}
 
+// GetPublisherApps returns the private apps served by the publisher with the given ID.
+// It forwards to c.Publishers.Apps.
+func (c *Client) GetPublisherApps(ctx context.Context, publisherID int) ([]PublisherApp, error) {
+	return c.Publishers.Apps(ctx, publisherID)
 }
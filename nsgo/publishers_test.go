@@ -0,0 +1,249 @@
+package nsgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestPublishers(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+
+	created, err := nsclient.CreatePublisher(nsgo.PublisherOptions{Name: "pub-1", Lbrokerconnect: nsgo.Bool(true)})
+	if err != nil {
+		t.Fatalf("CreatePublisher: %v", err)
+	}
+	if created.Name != "pub-1" || created.ID == 0 {
+		t.Fatalf("CreatePublisher = %+v", created)
+	}
+	id := strconv.Itoa(created.ID)
+
+	if _, err := nsclient.CreatePublisher(nsgo.PublisherOptions{Name: "pub-1"}); !errors.Is(err, nsgo.ErrConflict) {
+		t.Errorf("CreatePublisher duplicate: err = %v, want ErrConflict", err)
+	}
+
+	got, err := nsclient.GetPublisherId(nsgo.PublisherOptions{Id: id})
+	if err != nil {
+		t.Fatalf("GetPublisherId: %v", err)
+	}
+	if got.ID != created.ID || got.Status != "not registered" {
+		t.Errorf("GetPublisherId = %+v", got)
+	}
+
+	srv.AddPublisher(nsgotest.Publisher{Name: "pub-2", Status: "connected", Registered: true, Version: "97.0.0"})
+	list, err := nsclient.GetPublishers()
+	if err != nil {
+		t.Fatalf("GetPublishers: %v", err)
+	}
+	if len(list.Publishers) != 2 {
+		t.Fatalf("GetPublishers returned %d publishers, want 2", len(list.Publishers))
+	}
+	if !list.Publishers[0].Lbrokerconnect || list.Publishers[1].Assessment.Version != "97.0.0" {
+		t.Errorf("GetPublishers = %+v", list.Publishers)
+	}
+
+	filtered, err := nsclient.GetPublishersWithFilter(`publisher_name eq "pub-2"`)
+	if err != nil {
+		t.Fatalf("GetPublishersWithFilter: %v", err)
+	}
+	if len(filtered.Publishers) != 1 || filtered.Publishers[0].PublisherName != "pub-2" {
+		t.Errorf("GetPublishersWithFilter = %+v", filtered.Publishers)
+	}
+
+	if _, err := nsclient.UpdatePublisher(nsgo.PublisherOptions{Id: id, Lbrokerconnect: nsgo.Bool(false)}); err != nil {
+		t.Fatalf("UpdatePublisher: %v", err)
+	}
+	if p, _ := srv.Publisher(created.ID); p.Lbrokerconnect || p.Name != "pub-1" {
+		t.Errorf("after UpdatePublisher, publisher = %+v", p)
+	}
+
+	replaced, err := nsclient.ReplacePublisher(nsgo.PublisherOptions{Id: id, Name: "pub-renamed"})
+	if err != nil {
+		t.Fatalf("ReplacePublisher: %v", err)
+	}
+	if p := replaced.(*nsgo.Publisher); p.Name != "pub-renamed" {
+		t.Errorf("ReplacePublisher = %+v", p)
+	}
+
+	token, err := nsclient.GetToken(nsgo.PublisherOptions{Id: id})
+	if err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+	if token.Token == "" {
+		t.Error("GetToken returned an empty token")
+	}
+
+	if _, err := nsclient.DeletePublisher(nsgo.PublisherOptions{Id: id}); err != nil {
+		t.Fatalf("DeletePublisher: %v", err)
+	}
+	_, err = nsclient.GetPublisherId(nsgo.PublisherOptions{Id: id})
+	var apiErr *nsgo.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 || !errors.Is(err, nsgo.ErrNotFound) {
+		t.Errorf("GetPublisherId after delete: err = %v, want a 404 APIError", err)
+	}
+}
+
+func TestPublishersBulkUpgrade(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+	ctx := context.Background()
+
+	upgraded := srv.AddPublisher(nsgotest.Publisher{Name: "pub-1", Version: "96.0.0"})
+	failed := srv.AddPublisher(nsgotest.Publisher{Name: "pub-2", Version: "96.0.0"})
+	pending := srv.AddPublisher(nsgotest.Publisher{Name: "pub-3", Version: "96.0.0"})
+	unchanged := srv.AddPublisher(nsgotest.Publisher{Name: "pub-4", Version: "96.0.0"})
+	srv.AddPublisher(nsgotest.Publisher{Name: "pub-5", Version: "96.0.0"})
+
+	outcomes, err := nsclient.Publishers.BulkUpgrade(ctx, []int{upgraded, failed, pending, unchanged, 999})
+	if err != nil {
+		t.Fatalf("Publishers.BulkUpgrade: %v", err)
+	}
+	want := []nsgo.PublisherUpgradeOutcome{
+		{ID: upgraded, Name: "pub-1", Requested: true},
+		{ID: failed, Name: "pub-2", Requested: true},
+		{ID: pending, Name: "pub-3", Requested: true},
+		{ID: unchanged, Name: "pub-4", Requested: true},
+		{ID: 999},
+	}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("Publishers.BulkUpgrade = %+v, want %+v", outcomes, want)
+	}
+
+	srv.UpdatePublisher(upgraded, func(p *nsgotest.Publisher) {
+		p.UpgradeRequest, p.Version = false, "97.0.0.1234"
+	})
+	srv.UpdatePublisher(unchanged, func(p *nsgotest.Publisher) { p.UpgradeRequest = false })
+	srv.UpdatePublisher(failed, func(p *nsgotest.Publisher) {
+		p.UpgradeRequest, p.UpgradeFailedReason, p.UpgradeFailedVersion = false, "download failed", "97.0.0"
+	})
+	srv.UpdatePublisher(pending, func(p *nsgotest.Publisher) { p.Upstat = "downloading" })
+
+	list, err := nsclient.Publishers.List(ctx)
+	if err != nil {
+		t.Fatalf("Publishers.List: %v", err)
+	}
+	results := list.UpgradeResults("97.0.0", upgraded, failed, pending, unchanged)
+	if len(results) != 4 {
+		t.Fatalf("UpgradeResults returned %d results, want 4", len(results))
+	}
+	if r := results[0]; r.State != nsgo.PublisherUpgradeDone || r.Version != "97.0.0.1234" {
+		t.Errorf("upgraded publisher: %+v", r)
+	}
+	if r := results[1]; r.State != nsgo.PublisherUpgradeFailed || r.FailedReason.Detail != "download failed" {
+		t.Errorf("failed publisher: %+v", r)
+	}
+	if r := results[2]; r.State != nsgo.PublisherUpgradePending || r.Upstat != "downloading" {
+		t.Errorf("pending publisher: %+v", r)
+	}
+	if r := results[3]; r.State != nsgo.PublisherUpgradeUnchanged || r.Version != "96.0.0" {
+		t.Errorf("publisher still on its version: %+v", r)
+	}
+
+	if _, err := nsclient.Publishers.BulkUpgrade(ctx, nil); err == nil {
+		t.Error("Publishers.BulkUpgrade without IDs succeeded")
+	}
+}
+
+// A publisher returned without its upgrade_request field is not reported as requested.
+func TestPublishersBulkUpgradeWithoutField(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","data":{"publishers":[{"id":1,"name":"pub-1"},{"id":2,"name":"pub-2","upgrade_request":true}]}}`))
+	}))
+	defer srv.Close()
+	nsclient, _ := nsgo.New(srv.URL)
+
+	outcomes, err := nsclient.BulkUpgradePublishers(context.Background(), []int{1, 2})
+	if err != nil {
+		t.Fatalf("BulkUpgradePublishers: %v", err)
+	}
+	want := []nsgo.PublisherUpgradeOutcome{{ID: 1, Name: "pub-1"}, {ID: 2, Name: "pub-2", Requested: true}}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("BulkUpgradePublishers = %+v, want %+v", outcomes, want)
+	}
+}
+
+func TestPublisherApps(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+	ctx := context.Background()
+
+	primary := srv.AddPublisher(nsgotest.Publisher{Name: "pub-1"})
+	backup := srv.AddPublisher(nsgotest.Publisher{Name: "pub-2"})
+	wiki := srv.AddPrivateApp(nsgotest.PrivateApp{Name: "wiki", Host: "wiki.internal", PublisherIDs: []int{primary, backup}, Reachable: true,
+		Protocols: []nsgotest.Protocol{{Type: "tcp", Port: "443"}}})
+	srv.AddPrivateApp(nsgotest.PrivateApp{Name: "git", Host: "git.internal", PublisherIDs: []int{backup}})
+
+	apps, err := nsclient.Publishers.Apps(ctx, primary)
+	if err != nil {
+		t.Fatalf("Publishers.Apps: %v", err)
+	}
+	want := []nsgo.PublisherApp{{
+		ID:           nsgo.FlexInt(wiki),
+		Name:         "wiki",
+		Host:         "wiki.internal",
+		Protocols:    []nsgo.Protocol{{Type: "tcp", Port: "443"}},
+		Primary:      true,
+		Reachability: nsgo.PrivateAppReachability{Reachable: true},
+	}}
+	if !reflect.DeepEqual(apps, want) {
+		t.Errorf("Publishers.Apps = %+v, want %+v", apps, want)
+	}
+
+	apps, err = nsclient.GetPublisherApps(ctx, backup)
+	if err != nil {
+		t.Fatalf("GetPublisherApps: %v", err)
+	}
+	if len(apps) != 2 || apps[0].Primary || apps[1].Name != "git" || apps[1].Reachability.Reachable {
+		t.Errorf("GetPublisherApps of the backup publisher = %+v", apps)
+	}
+
+	if _, err := nsclient.Publishers.Apps(ctx, 999); !errors.Is(err, nsgo.ErrNotFound) {
+		t.Errorf("Publishers.Apps of an unknown publisher: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPublisherAlertsConfiguration(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+	ctx := context.Background()
+
+	config, err := nsclient.Publishers.AlertsConfiguration(ctx)
+	if err != nil {
+		t.Fatalf("Publishers.AlertsConfiguration: %v", err)
+	}
+	if len(config.AdminUsers) != 0 || len(config.EventTypes) != 0 {
+		t.Errorf("Publishers.AlertsConfiguration = %+v, want no alerts", config)
+	}
+
+	want := nsgo.PublisherAlertsConfiguration{
+		AdminUsers: []string{"noc@example.com"},
+		EventTypes: []nsgo.PublisherAlertEvent{nsgo.PublisherAlertConnectionFailed, nsgo.PublisherAlertUpgradeFailed},
+	}
+	updated, err := nsclient.Publishers.UpdateAlertsConfiguration(ctx, want)
+	if err != nil {
+		t.Fatalf("Publishers.UpdateAlertsConfiguration: %v", err)
+	}
+	if !reflect.DeepEqual(*updated, want) {
+		t.Errorf("Publishers.UpdateAlertsConfiguration = %+v, want %+v", updated, want)
+	}
+	if stored := srv.AlertsConfiguration(); !reflect.DeepEqual(stored.EventTypes, []string{"CONNECTION_FAILED", "UPGRADE_FAILED"}) {
+		t.Errorf("server stored %+v", stored)
+	}
+
+	want.EventTypes = append(want.EventTypes, "PUBLISHER_DELETED")
+	if _, err := nsclient.Publishers.UpdateAlertsConfiguration(ctx, want); !errors.Is(err, nsgo.ErrBadRequest) {
+		t.Errorf("Publishers.UpdateAlertsConfiguration with an unknown event: err = %v, want ErrBadRequest", err)
+	}
+}
@@ -1,212 +1,225 @@
 package nsgo
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
+	"time"
 )
 
 // Struct that defines data returned when getting a list of publisher upgrade profiles
 
 type PublisherUpgradeProfiles struct {
-	UpgradeProfiles []struct {
-		CreatedAt              string `json:"created_at"`
-		DockerTag              string `json:"docker_tag"`
-		Enabled                bool   `json:"enabled"`
-		ExternalID             int    `json:"external_id"`
-		Frequency              string `json:"frequency"`
-		ID                     int    `json:"id"`
-		Name                   string `json:"name"`
-		NextUpdateTime         int    `json:"next_update_time"`
-		NumAssociatedPublisher int    `json:"num_associated_publisher"`
-		ReleaseType            string `json:"release_type"`
-		Timezone               string `json:"timezone"`
-		UpdatedAt              string `json:"updated_at"`
-		UpgradingStage         int    `json:"upgrading_stage"`
-		WillStart              bool   `json:"will_start"`
-	} `json:"upgrade_profiles"`
+	UpgradeProfiles []PublisherUpgradeProfile `json:"upgrade_profiles"`
 }
 
+// PublisherUpgradeProfile defines an individual publisher upgrade profile.
+// NextUpdateTime is the zero time when no upgrade is scheduled.
 type PublisherUpgradeProfile struct {
-	CreatedAt      string `json:"created_at"`
-	DockerTag      string `json:"docker_tag"`
-	Enabled        bool   `json:"enabled"`
-	Frequency      string `json:"frequency"`
-	ID             int    `json:"id"`
-	Name           string `json:"name"`
-	NextUpdateTime int    `json:"next_update_time"`
-	ReleaseType    string `json:"release_type"`
-	Timezone       string `json:"timezone"`
-	UpdatedAt      string `json:"updated_at"`
-	UpgradingStage int    `json:"upgrading_stage"`
-	WillStart      bool   `json:"will_start"`
+	CreatedAt              time.Time `json:"created_at"`
+	DockerTag              string    `json:"docker_tag"`
+	Enabled                bool      `json:"enabled"`
+	ExternalID             int       `json:"external_id,omitempty"`
+	Frequency              string    `json:"frequency"`
+	ID                     int       `json:"id"`
+	Name                   string    `json:"name"`
+	NextUpdateTime         time.Time `json:"next_update_time"`
+	NumAssociatedPublisher int       `json:"num_associated_publisher,omitempty"`
+	ReleaseType            string    `json:"release_type"`
+	Timezone               string    `json:"timezone"`
+	UpdatedAt              time.Time `json:"updated_at"`
+	UpgradingStage         int       `json:"upgrading_stage"`
+	WillStart              bool      `json:"will_start"`
 }
 
+// UnmarshalJSON decodes the timestamps of the profile, which the API sends either as
+// date strings or, for next_update_time, as unix timestamps.
+func (p *PublisherUpgradeProfile) UnmarshalJSON(data []byte) error {
+	type plain PublisherUpgradeProfile
+	aux := struct {
+		*plain
+		CreatedAt      json.RawMessage `json:"created_at"`
+		NextUpdateTime json.RawMessage `json:"next_update_time"`
+		UpdatedAt      json.RawMessage `json:"updated_at"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if p.CreatedAt, err = parseTimestamp(aux.CreatedAt); err != nil {
+		return err
+	}
+	if p.NextUpdateTime, err = parseTimestamp(aux.NextUpdateTime); err != nil {
+		return err
+	}
+	if p.UpdatedAt, err = parseTimestamp(aux.UpdatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PublisherUpgradeProfileOptions defines details used in GET by ID, Create, Update and Delete methods.
+// Set ID to identify the profile of the GET by ID, Update and Delete methods.
+//
+//	newprofile := nsgo.PublisherUpgradeProfileOptions{
+//		Name:        "Weekly",
+//		Timezone:    "America/Los_Angeles",
+//		ReleaseType: "Beta",
+//		DockerTag:   "latest",
+//		Frequency:   "0 0 * * SUN",
+//	}
 type PublisherUpgradeProfileOptions struct {
-	ExternalID  string `json:"external_id,omitempty"`  // Used when deleting a publisher upgrade profile
-	ID          string `json:"id,omitempty"`           // Used when deleting a publisher upgrade profile
+	// ExternalID identifies the profile of the GET by ID and Delete methods, which use it in place
+	// of ID when both are set.
+	//
+	// Deprecated: Set ID, which all the methods use.
+	ExternalID  string `json:"external_id,omitempty"`
+	ID          string `json:"id,omitempty"`           // Identifies the profile of the GET by ID, Update and Delete methods
 	Name        string `json:"name,omitempty"`         // Used when creating a publisher upgrade profile
 	Timezone    string `json:"timezone,omitempty"`     // Used when creating a publisher upgrade profile
 	ReleaseType string `json:"release_type,omitempty"` // Used when creating a publisher upgrade profile
 	DockerTag   string `json:"docker_tag,omitempty"`   // Used when creating a publisher upgrade profile
 	Frequency   string `json:"frequency,omitempty"`    // Used when creating a publisher upgrade profile
-	Enabled     bool   `json:"enabled,omitempty"`      // Used when creating a publisher upgrade profile
+	Enabled     *bool  `json:"enabled,omitempty"`      // Used when creating a publisher upgrade profile
 }
 
-// GetPublisherUpgradeProfiles function is used to build API request which is sent to sendRequest().
-// It is called using the client struct, and returns an interface with a list of Publisher Upgrade Profiles.
-// The output can be marshalled into a PublisherUpgradeProfiles struct.
-
-func (c *Client) GetPublisherUpgradeProfiles() (interface{}, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/infrastructure/publisherupgradeprofiles", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
-
-	if res.Status == "success" {
-		return res.Data, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
+// profileID returns the identifier used in the URL of the profile. The GET by ID and Delete methods
+// used to take it from ExternalID only, and Update from ID only; each still prefers its own field,
+// with externalFirst, so that callers setting both keep hitting the same URL.
+func (o PublisherUpgradeProfileOptions) profileID(externalFirst bool) string {
+	if o.ID == "" || (externalFirst && o.ExternalID != "") {
+		return o.ExternalID
 	}
+	return o.ID
 }
 
-// GetPublisherUpgradeProfileId function is used to build API request which is sent to sendRequest().
+// upgradeProfilesService implements UpgradeProfilesService for a Client.
+type upgradeProfilesService struct {
+	c *Client
+}
 
-func (c *Client) GetPublisherUpgradeProfileId(options PublisherUpgradeProfileOptions) (*successResponse, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/infrastructure/publisherupgradeprofiles/%s", c.BaseURL, options.ExternalID), nil)
+func (s *upgradeProfilesService) List(ctx context.Context) (*PublisherUpgradeProfiles, error) {
+	profiles, err := doJSON[PublisherUpgradeProfiles](ctx, s.c, "GET", "/api/v2/infrastructure/publisherupgradeprofiles", nil)
 	if err != nil {
 		return nil, err
 	}
+	return &profiles, nil
+}
+
+func (s *upgradeProfilesService) Pages(opts ListOptions) *Pager[PublisherUpgradeProfile] {
+	return NewPager(opts, listFetcher(s.c, "/api/v2/infrastructure/publisherupgradeprofiles", func(l *PublisherUpgradeProfiles) []PublisherUpgradeProfile {
+		return l.UpgradeProfiles
+	}))
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+func (s *upgradeProfilesService) Get(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	profile, err := doJSON[PublisherUpgradeProfile](ctx, s.c, "GET", "/api/v2/infrastructure/publisherupgradeprofiles/"+options.profileID(true), nil)
+	if err != nil {
 		return nil, err
 	}
+	return &profile, nil
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
+func (s *upgradeProfilesService) Create(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	return verifiedCreate(ctx, s.c, func(ctx context.Context) (*PublisherUpgradeProfile, error) {
+		profile, err := doJSON[PublisherUpgradeProfile](ctx, s.c, "POST", "/api/v2/infrastructure/publisherupgradeprofiles", options)
 		if err != nil {
 			return nil, err
 		}
-		dataStruct := successResponse{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+		return &profile, nil
+	}, func(ctx context.Context) (*PublisherUpgradeProfile, error) {
+		profiles, err := s.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range profiles.UpgradeProfiles {
+			if p.Name == options.Name {
+				return &p, nil
+			}
+		}
+		return nil, nil
+	})
 }
 
-// CreatePublisherUpgradeProfile function is used to build API request which is sent to sendRequest().
-// It is called using the client struct, and returns.
-
-func (c *Client) CreatePublisherUpgradeProfile(options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
-	//Define JSON Body
-	json_body, err := json.Marshal(options)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
-
-	//Setup the HTTP Request
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/infrastructure/publisherupgradeprofiles", c.BaseURL), bytes.NewBuffer(json_body))
+func (s *upgradeProfilesService) Update(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	profile, err := doJSON[PublisherUpgradeProfile](ctx, s.c, "PUT", "/api/v2/infrastructure/publisherupgradeprofiles/"+options.profileID(false), options)
 	if err != nil {
 		return nil, err
 	}
+	return &profile, nil
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+func (s *upgradeProfilesService) Delete(ctx context.Context, options PublisherUpgradeProfileOptions) error {
+	_, _, err := s.c.do(ctx, "DELETE", "/api/v2/infrastructure/publisherupgradeprofiles/"+options.profileID(true), nil)
+	return err
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := PublisherUpgradeProfile{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+// GetPublisherUpgradeProfiles function is used to build API request which is sent to sendRequest().
+// It is called using the client struct, and returns the list of Publisher Upgrade Profiles.
+//
+// Deprecated: Use c.UpgradeProfiles.List.
+func (c *Client) GetPublisherUpgradeProfiles() (*PublisherUpgradeProfiles, error) {
+	return c.UpgradeProfiles.List(context.Background())
+}
 
+// GetPublisherUpgradeProfilesWithContext is like GetPublisherUpgradeProfiles but uses ctx for the request.
+// It forwards to c.UpgradeProfiles.List.
+func (c *Client) GetPublisherUpgradeProfilesWithContext(ctx context.Context) (*PublisherUpgradeProfiles, error) {
+	return c.UpgradeProfiles.List(ctx)
 }
 
-// UpdatePublisherUpgradeProfile function is used to build API request which is sent to sendRequest().
+// GetPublisherUpgradeProfileId function is used to build API request which is sent to sendRequest().
+//
+// Deprecated: Use c.UpgradeProfiles.Get.
+func (c *Client) GetPublisherUpgradeProfileId(options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	return c.UpgradeProfiles.Get(context.Background(), options)
+}
 
-func (c *Client) UpdatePublisherUpgradeProfile(options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
-	//Define JSON Body
-	json_body, err := json.Marshal(options)
-	if err != nil {
-		return nil, errors.New("bad json options")
-	}
+// GetPublisherUpgradeProfileIdWithContext is like GetPublisherUpgradeProfileId but uses ctx for the request.
+// It forwards to c.UpgradeProfiles.Get.
+func (c *Client) GetPublisherUpgradeProfileIdWithContext(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	return c.UpgradeProfiles.Get(ctx, options)
+}
 
-	//Setup the HTTP Request
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/v2/infrastructure/publisherupgradeprofiles/%s", c.BaseURL, options.ID), bytes.NewBuffer(json_body))
-	if err != nil {
-		return nil, err
-	}
+// CreatePublisherUpgradeProfile function is used to build API request which is sent to sendRequest().
+// It is called using the client struct, and returns.
+//
+// Deprecated: Use c.UpgradeProfiles.Create.
+func (c *Client) CreatePublisherUpgradeProfile(options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	return c.UpgradeProfiles.Create(context.Background(), options)
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
-	}
+// CreatePublisherUpgradeProfileWithContext is like CreatePublisherUpgradeProfile but uses ctx for the request.
+// It forwards to c.UpgradeProfiles.Create.
+func (c *Client) CreatePublisherUpgradeProfileWithContext(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	return c.UpgradeProfiles.Create(ctx, options)
+}
 
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := PublisherUpgradeProfile{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+// UpdatePublisherUpgradeProfile function is used to build API request which is sent to sendRequest().
+//
+// Deprecated: Use c.UpgradeProfiles.Update.
+func (c *Client) UpdatePublisherUpgradeProfile(options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	return c.UpgradeProfiles.Update(context.Background(), options)
+}
 
+// UpdatePublisherUpgradeProfileWithContext is like UpdatePublisherUpgradeProfile but uses ctx for the request.
+// It forwards to c.UpgradeProfiles.Update.
+func (c *Client) UpdatePublisherUpgradeProfileWithContext(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error) {
+	return c.UpgradeProfiles.Update(ctx, options)
 }
 
 // DeletePublisherUpgradeProfile function is used to build API request which is sent to sendRequest().
-
+//
+// Deprecated: Use c.UpgradeProfiles.Delete.
 func (c *Client) DeletePublisherUpgradeProfile(options PublisherUpgradeProfileOptions) (*successResponse, error) {
-	//Setup the HTTP Request
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/v2/infrastructure/publisherupgradeprofiles/%s", c.BaseURL, options.ExternalID), nil)
-	if err != nil {
-		return nil, err
-	}
+	return c.DeletePublisherUpgradeProfileWithContext(context.Background(), options)
+}
 
-	res := successResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
+// DeletePublisherUpgradeProfileWithContext is like DeletePublisherUpgradeProfile but uses ctx for the request.
+// It forwards to c.UpgradeProfiles.Delete.
+func (c *Client) DeletePublisherUpgradeProfileWithContext(ctx context.Context, options PublisherUpgradeProfileOptions) (*successResponse, error) {
+	if err := c.UpgradeProfiles.Delete(ctx, options); err != nil {
 		return nil, err
 	}
-
-	if res.Status == "success" {
-		jsonData, err := json.Marshal(res.Data)
-		if err != nil {
-			return nil, err
-		}
-		dataStruct := successResponse{}
-		json.Unmarshal(jsonData, &dataStruct)
-		return &dataStruct, nil
-	} else if res.Status == "error" {
-		return nil, errors.New(res.Message)
-	} else {
-		return nil, errors.New("Unkown Status: " + res.Status)
-	}
+	return &successResponse{Status: "success"}, nil
 }
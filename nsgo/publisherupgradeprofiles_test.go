@@ -0,0 +1,160 @@
+package nsgo_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestPublisherUpgradeProfiles(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+
+	created, err := nsclient.CreatePublisherUpgradeProfile(nsgo.PublisherUpgradeProfileOptions{
+		Name:        "weekly",
+		Timezone:    "US/Pacific",
+		ReleaseType: "Beta",
+		Frequency:   "0 0 * * SUN",
+		Enabled:     nsgo.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("CreatePublisherUpgradeProfile: %v", err)
+	}
+	if created.ID == 0 || created.Name != "weekly" || !created.Enabled || created.CreatedAt.IsZero() {
+		t.Fatalf("CreatePublisherUpgradeProfile = %+v", created)
+	}
+	id := strconv.Itoa(created.ID)
+
+	next := time.Date(2030, 1, 6, 0, 0, 0, 0, time.UTC)
+	srv.AddUpgradeProfile(nsgotest.UpgradeProfile{Name: "monthly", NextUpdateTime: next})
+	profiles, err := nsclient.GetPublisherUpgradeProfiles()
+	if err != nil {
+		t.Fatalf("GetPublisherUpgradeProfiles: %v", err)
+	}
+	if len(profiles.UpgradeProfiles) != 2 || !profiles.UpgradeProfiles[1].NextUpdateTime.Equal(next) {
+		t.Errorf("GetPublisherUpgradeProfiles = %+v", profiles.UpgradeProfiles)
+	}
+
+	got, err := nsclient.GetPublisherUpgradeProfileId(nsgo.PublisherUpgradeProfileOptions{ExternalID: strconv.Itoa(created.ExternalID)})
+	if err != nil {
+		t.Fatalf("GetPublisherUpgradeProfileId: %v", err)
+	}
+	if got.ID != created.ID {
+		t.Errorf("GetPublisherUpgradeProfileId = %+v", got)
+	}
+
+	updated, err := nsclient.UpdatePublisherUpgradeProfile(nsgo.PublisherUpgradeProfileOptions{ID: id, Enabled: nsgo.Bool(false)})
+	if err != nil {
+		t.Fatalf("UpdatePublisherUpgradeProfile: %v", err)
+	}
+	if updated.Enabled || updated.Name != "weekly" {
+		t.Errorf("UpdatePublisherUpgradeProfile = %+v", updated)
+	}
+
+	if _, err := nsclient.DeletePublisherUpgradeProfile(nsgo.PublisherUpgradeProfileOptions{ID: id}); err != nil {
+		t.Fatalf("DeletePublisherUpgradeProfile: %v", err)
+	}
+	if _, err := nsclient.GetPublisherUpgradeProfileId(nsgo.PublisherUpgradeProfileOptions{ID: id}); err == nil {
+		t.Error("GetPublisherUpgradeProfileId after delete succeeded")
+	}
+}
+
+// Each method keeps identifying profiles by the field it used before ID and ExternalID both worked.
+func TestPublisherUpgradeProfileIdentifiers(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+	ctx := context.Background()
+
+	id := srv.AddUpgradeProfile(nsgotest.UpgradeProfile{Name: "weekly", ExternalID: 900})
+	both := nsgo.PublisherUpgradeProfileOptions{ID: strconv.Itoa(id), ExternalID: "900"}
+	lastPath := func() string {
+		reqs := srv.Requests()
+		return reqs[len(reqs)-1].Path
+	}
+	const prefix = "/api/v2/infrastructure/publisherupgradeprofiles/"
+
+	for _, tc := range []struct {
+		name    string
+		options nsgo.PublisherUpgradeProfileOptions
+		want    string
+	}{
+		{"ID", nsgo.PublisherUpgradeProfileOptions{ID: strconv.Itoa(id)}, strconv.Itoa(id)},
+		{"ExternalID", nsgo.PublisherUpgradeProfileOptions{ExternalID: "900"}, "900"},
+		{"both", both, "900"},
+	} {
+		if _, err := nsclient.UpgradeProfiles.Get(ctx, tc.options); err != nil {
+			t.Fatalf("Get by %s: %v", tc.name, err)
+		}
+		if got := lastPath(); got != prefix+tc.want {
+			t.Errorf("Get by %s requested %s, want %s", tc.name, got, prefix+tc.want)
+		}
+	}
+
+	both.Enabled = nsgo.Bool(true)
+	if _, err := nsclient.UpgradeProfiles.Update(ctx, both); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := lastPath(); got != prefix+strconv.Itoa(id) {
+		t.Errorf("Update requested %s, want %s", got, prefix+strconv.Itoa(id))
+	}
+	reqs := srv.Requests()
+	if body := string(reqs[len(reqs)-1].Body); body != `{"external_id":"900","id":"`+strconv.Itoa(id)+`","enabled":true}` {
+		t.Errorf("Update sent %s, want the identifiers in the body", body)
+	}
+
+	if err := nsclient.UpgradeProfiles.Delete(ctx, both); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got := lastPath(); got != prefix+"900" {
+		t.Errorf("Delete requested %s, want %s", got, prefix+"900")
+	}
+}
+
+func TestPublisherReleases(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+	ctx := context.Background()
+
+	srv.SetReleases([]nsgotest.Release{
+		{Name: "Latest", Version: "97.0.0", DockerTag: "97.0.0.1234", ReleaseType: "Latest"},
+		{Name: "Latest - 1", Version: "96.0.0", DockerTag: "96.0.0.1100", ReleaseType: "Latest-1"},
+	})
+	srv.AddPublisher(nsgotest.Publisher{Name: "pub-1", Version: "96.0.0"})
+
+	releases, err := nsclient.GetPublisherReleases(ctx)
+	if err != nil {
+		t.Fatalf("GetPublisherReleases: %v", err)
+	}
+	want := []nsgo.PublisherRelease{
+		{Name: "Latest", Version: "97.0.0", DockerTag: "97.0.0.1234", ReleaseType: "Latest"},
+		{Name: "Latest - 1", Version: "96.0.0", DockerTag: "96.0.0.1100", ReleaseType: "Latest-1", PublisherCount: 1},
+	}
+	if !reflect.DeepEqual(releases, want) {
+		t.Errorf("GetPublisherReleases = %+v, want %+v", releases, want)
+	}
+
+	if err := nsgo.ValidateDockerTag(releases, "95.0.0.1000"); !errors.Is(err, nsgo.ErrUnknownRelease) {
+		t.Errorf("ValidateDockerTag with an unknown docker tag: err = %v, want ErrUnknownRelease", err)
+	}
+	if err := nsgo.ValidateDockerTag(releases, "97.0.0.1234"); err != nil {
+		t.Errorf("ValidateDockerTag: %v", err)
+	}
+
+	// Create does not check the docker tag itself.
+	options := nsgo.PublisherUpgradeProfileOptions{Name: "weekly", DockerTag: "latest", Frequency: "0 0 * * SUN"}
+	if _, err := nsclient.UpgradeProfiles.Create(ctx, options); err != nil {
+		t.Errorf("UpgradeProfiles.Create: %v", err)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("%d requests sent, want Releases and Create only", n)
+	}
+}
@@ -0,0 +1,154 @@
+package nsgo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// PublisherUpgradeOutcome is the outcome of the upgrade request of a publisher, as returned by
+// PublishersService.BulkUpgrade.
+type PublisherUpgradeOutcome struct {
+	ID   int
+	Name string
+	// Requested reports whether the tenant recorded the upgrade request. It is false for
+	// publishers the tenant did not return, i.e. unknown IDs, and for those it returned without
+	// their upgrade_request field.
+	Requested bool
+}
+
+// bulkPublishersRequest is the body of a bulk publishers request.
+type bulkPublishersRequest struct {
+	Publishers struct {
+		Apply map[string]interface{} `json:"apply"`
+		ID    []string               `json:"id"`
+	} `json:"publishers"`
+}
+
+// bulkPublishersResponse is the payload of a bulk publishers response.
+type bulkPublishersResponse struct {
+	Publishers []struct {
+		ID             FlexInt   `json:"id"`
+		Name           string    `json:"name"`
+		UpgradeRequest *FlexBool `json:"upgrade_request"`
+	} `json:"publishers"`
+}
+
+func (s *publishersService) BulkUpgrade(ctx context.Context, ids []int) ([]PublisherUpgradeOutcome, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("nsgo: no publishers to upgrade")
+	}
+	var body bulkPublishersRequest
+	body.Publishers.Apply = map[string]interface{}{"upgrade_request": true}
+	for _, id := range ids {
+		body.Publishers.ID = append(body.Publishers.ID, strconv.Itoa(id))
+	}
+
+	res, err := doJSON[bulkPublishersResponse](ctx, s.c, "PUT", "/api/v2/infrastructure/publishers/bulk", body)
+	if err != nil {
+		return nil, err
+	}
+	outcomes := make([]PublisherUpgradeOutcome, len(ids))
+	for i, id := range ids {
+		outcomes[i].ID = id
+		for _, p := range res.Publishers {
+			if int(p.ID) == id {
+				outcomes[i].Name = p.Name
+				outcomes[i].Requested = p.UpgradeRequest != nil && bool(*p.UpgradeRequest)
+			}
+		}
+	}
+	return outcomes, nil
+}
+
+// BulkUpgradePublishers requests the upgrade of the publishers with the given IDs, and returns the
+// outcome for each of them in the same order. It forwards to c.Publishers.BulkUpgrade.
+func (c *Client) BulkUpgradePublishers(ctx context.Context, ids []int) ([]PublisherUpgradeOutcome, error) {
+	return c.Publishers.BulkUpgrade(ctx, ids)
+}
+
+// PublisherUpgradeState is the stage of the upgrade of a publisher.
+type PublisherUpgradeState string
+
+const (
+	// PublisherUpgradePending means the upgrade was requested and has not completed yet.
+	PublisherUpgradePending PublisherUpgradeState = "pending"
+	// PublisherUpgradeFailed means the publisher failed to upgrade.
+	PublisherUpgradeFailed PublisherUpgradeState = "failed"
+	// PublisherUpgradeDone means the publisher runs the version it was upgraded to.
+	PublisherUpgradeDone PublisherUpgradeState = "upgraded"
+	// PublisherUpgradeUnchanged means the upgrade is neither pending nor failed, yet the
+	// publisher does not run the version it was upgraded to.
+	PublisherUpgradeUnchanged PublisherUpgradeState = "unchanged"
+)
+
+// PublisherUpgradeResult reports the upgrade of a publisher, as read from a PublishersList.
+type PublisherUpgradeResult struct {
+	ID   int
+	Name string
+	// State is the stage of the upgrade.
+	State PublisherUpgradeState
+	// Version is the version the publisher runs.
+	Version string
+	// Upstat is the progress reported by the tenant while the upgrade is pending.
+	Upstat string
+	// FailedReason is set when State is PublisherUpgradeFailed.
+	FailedReason PublisherUpgradeFailedReason
+}
+
+// UpgradeResults reports the upgrade to the target version of the publishers with the given IDs,
+// or of every publisher of l when there are none, from their UpgradeRequest, UpgradeStatus,
+// UpgradeFailedReason and version. It is meant for publishers whose upgrade was requested, i.e.
+// with PublishersService.BulkUpgrade, listed again once they had time to upgrade. target is the
+// version of the release they were upgraded to, as in PublisherRelease.Version: a publisher is
+// reported as upgraded only when it runs that version, or a build of it such as "97.0.0.1234" for
+// "97.0.0". A failure to upgrade to the version the publisher now runs is stale, and ignored.
+//
+//	list, err := nsclient.Publishers.List(ctx)
+//	for _, r := range list.UpgradeResults(release.Version, ids...) {
+//		fmt.Println(r.Name, r.State, r.Version)
+//	}
+func (l *PublishersList) UpgradeResults(target string, ids ...int) []PublisherUpgradeResult {
+	var results []PublisherUpgradeResult
+	for _, p := range l.Publishers {
+		if len(ids) > 0 && !containsInt(ids, int(p.PublisherID)) {
+			continue
+		}
+		r := PublisherUpgradeResult{
+			ID:      int(p.PublisherID),
+			Name:    p.PublisherName,
+			Version: p.Assessment.Version,
+			Upstat:  p.UpgradeStatus.Upstat,
+		}
+		reason := p.UpgradeFailedReason
+		failed := (reason.Detail != "" || reason.ErrorCode != "") && (reason.Version == "" || reason.Version != r.Version)
+		switch {
+		case bool(p.UpgradeRequest):
+			r.State = PublisherUpgradePending
+		case target != "" && runsVersion(r.Version, target):
+			r.State = PublisherUpgradeDone
+		case failed:
+			r.State = PublisherUpgradeFailed
+			r.FailedReason = reason
+		default:
+			r.State = PublisherUpgradeUnchanged
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// runsVersion reports whether version is target, or a build of it.
+func runsVersion(version, target string) bool {
+	return version == target || strings.HasPrefix(version, target+".")
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
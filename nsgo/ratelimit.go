@@ -0,0 +1,262 @@
+package nsgo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint families sharing a rate limit budget on the tenant.
+const (
+	FamilyInfrastructure = "infrastructure"
+	FamilySteering       = "steering"
+	FamilyEvents         = "events"
+)
+
+// RateLimit defines a token bucket: Rate requests per second on average, with bursts of up to Burst requests.
+// A zero Rate disables client side throttling for the family.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// defaultRateLimits apply to the families WithRateLimit was not given for. They are deliberately
+// conservative; the tenant headers tighten them further when needed.
+var defaultRateLimits = map[string]RateLimit{
+	FamilyInfrastructure: {Rate: 4, Burst: 8},
+	FamilySteering:       {Rate: 4, Burst: 8},
+	FamilyEvents:         {Rate: 4, Burst: 8},
+}
+
+// RateLimitBudget reports the state of the rate limit budget of an endpoint family.
+type RateLimitBudget struct {
+	Family string
+	// Tokens is the number of requests the client may send right away.
+	Tokens float64
+	// Limit and Remaining are the values last reported by the tenant, or -1 when unknown.
+	Limit     int
+	Remaining int
+	// Reset is when the tenant said its window resets, if it did.
+	Reset time.Time
+	// BlockedUntil is set while requests are held back after a 429 or an exhausted window.
+	BlockedUntil time.Time
+}
+
+// RateLimitBudget returns the current budget for family (one of the Family constants).
+func (c *Client) RateLimitBudget(family string) RateLimitBudget {
+	if c.limiter == nil {
+		return RateLimitBudget{Family: family, Limit: -1, Remaining: -1}
+	}
+	return c.limiter.budget(family)
+}
+
+// WithRateLimit turns on client side throttling, with limit for family and 4 requests per second,
+// with bursts of 8, for the families without a limit of their own. Clients do not throttle
+// themselves otherwise.
+func WithRateLimit(family string, limit RateLimit) Option {
+	return func(o *clientOptions) error {
+		if o.rateLimits == nil {
+			o.rateLimits = map[string]RateLimit{}
+		}
+		o.rateLimits[family] = limit
+		return nil
+	}
+}
+
+// WithoutRateLimit disables client side throttling entirely, whatever the WithRateLimit options.
+func WithoutRateLimit() Option {
+	return func(o *clientOptions) error {
+		o.noRateLimit = true
+		return nil
+	}
+}
+
+// endpointFamily returns the family of an API path, i.e. "steering" for "/api/v2/steering/apps/private".
+func endpointFamily(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	parts := strings.SplitN(path, "/", 4)
+	if len(parts) >= 3 && parts[0] == "api" {
+		return parts[2]
+	}
+	return ""
+}
+
+// rateLimiter keeps one token bucket per endpoint family, shared by every goroutine using the client.
+type rateLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]RateLimit
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	limit        RateLimit
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	server       RateLimitInfo
+	reset        time.Time
+}
+
+func newRateLimiter(overrides map[string]RateLimit) *rateLimiter {
+	limits := make(map[string]RateLimit, len(defaultRateLimits)+len(overrides))
+	for k, v := range defaultRateLimits {
+		limits[k] = v
+	}
+	for k, v := range overrides {
+		limits[k] = v
+	}
+	return &rateLimiter{
+		limits:  limits,
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// get returns the bucket of family, refilled up to now. Callers hold l.mu.
+func (l *rateLimiter) get(family string, now time.Time) *bucket {
+	b, ok := l.buckets[family]
+	if !ok {
+		limit := l.limits[family]
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now, server: RateLimitInfo{Limit: -1, Remaining: -1}}
+		l.buckets[family] = b
+	}
+	if b.limit.Rate > 0 {
+		b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+		if b.tokens > float64(b.limit.Burst) {
+			b.tokens = float64(b.limit.Burst)
+		}
+	}
+	b.last = now
+	return b
+}
+
+// reserve takes a token from the bucket of family and returns how long the caller must wait before using it.
+func (l *rateLimiter) reserve(family string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.get(family, now)
+	var wait time.Duration
+	if b.limit.Rate > 0 {
+		b.tokens--
+		if b.tokens < 0 {
+			wait = time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+		}
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait
+}
+
+// cancel gives back a token reserved by a caller that gave up waiting.
+func (l *rateLimiter) cancel(family string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[family]; ok && b.limit.Rate > 0 {
+		b.tokens++
+	}
+}
+
+// wait blocks until a request to family may be sent or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context, family string) error {
+	d := l.reserve(family)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel(family)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// observe records the rate limit headers of a response and holds the family back
+// when the tenant reports a 429 or an exhausted window.
+func (l *rateLimiter) observe(family string, res *http.Response) {
+	info := parseRateLimit(res.Header)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.get(family, now)
+	if info.Limit >= 0 {
+		b.server.Limit = info.Limit
+	}
+	if info.Remaining >= 0 {
+		b.server.Remaining = info.Remaining
+	}
+	if info.Reset > 0 {
+		b.reset = now.Add(info.Reset)
+	}
+
+	var until time.Time
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		delay := info.RetryAfter
+		if delay == 0 {
+			delay = info.Reset
+		}
+		if delay == 0 {
+			delay = time.Second
+		}
+		until = now.Add(delay)
+		b.tokens = 0
+	case info.Remaining == 0 && info.Reset > 0:
+		until = now.Add(info.Reset)
+		b.tokens = 0
+	}
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+func (l *rateLimiter) budget(family string) RateLimitBudget {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.get(family, now)
+	budget := RateLimitBudget{
+		Family:    family,
+		Tokens:    b.tokens,
+		Limit:     b.server.Limit,
+		Remaining: b.server.Remaining,
+		Reset:     b.reset,
+	}
+	if b.tokens < 0 {
+		budget.Tokens = 0
+	}
+	if b.blockedUntil.After(now) {
+		budget.BlockedUntil = b.blockedUntil
+	}
+	return budget
+}
+
+// rateLimitTransport throttles every attempt sent through it, retries included.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	family := endpointFamily(req.URL.Path)
+	if err := t.limiter.wait(req.Context(), family); err != nil {
+		return nil, err
+	}
+	res, err := t.next.RoundTrip(req)
+	if res != nil {
+		t.limiter.observe(family, res)
+	}
+	return res, err
+}
@@ -0,0 +1,147 @@
+package nsgo
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a rateLimiter clock the tests move by hand.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newTestLimiter(limit RateLimit) (*rateLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	l := newRateLimiter(map[string]RateLimit{FamilyInfrastructure: limit})
+	l.now = clock.now
+	return l, clock
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	l, clock := newTestLimiter(RateLimit{Rate: 2, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		if d := l.reserve(FamilyInfrastructure); d != 0 {
+			t.Fatalf("reserve %d within the burst: wait %v, want 0", i, d)
+		}
+	}
+	if d := l.reserve(FamilyInfrastructure); d != 500*time.Millisecond {
+		t.Errorf("reserve on an empty bucket: wait %v, want 500ms", d)
+	}
+
+	// A second refills two tokens, one of which pays for the reservation above.
+	clock.advance(time.Second)
+	if d := l.reserve(FamilyInfrastructure); d != 0 {
+		t.Errorf("reserve after a refill: wait %v, want 0", d)
+	}
+	if d := l.reserve(FamilyInfrastructure); d != 500*time.Millisecond {
+		t.Errorf("reserve once the refill is spent: wait %v, want 500ms", d)
+	}
+
+	// The bucket never holds more than Burst tokens.
+	clock.advance(time.Hour)
+	if got := l.budget(FamilyInfrastructure).Tokens; got != 2 {
+		t.Errorf("Tokens after an hour = %v, want 2", got)
+	}
+
+	// Families without a limit are not throttled.
+	for i := 0; i < 10; i++ {
+		if d := l.reserve("other"); d != 0 {
+			t.Fatalf("reserve on an unlimited family: wait %v, want 0", d)
+		}
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{Rate: 1, Burst: 1})
+
+	l.reserve(FamilyInfrastructure)
+	if d := l.reserve(FamilyInfrastructure); d != time.Second {
+		t.Fatalf("reserve on an empty bucket: wait %v, want 1s", d)
+	}
+	l.cancel(FamilyInfrastructure)
+	if d := l.reserve(FamilyInfrastructure); d != time.Second {
+		t.Errorf("reserve after cancel: wait %v, want 1s", d)
+	}
+
+	// wait gives its token back when the context is done first.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx, FamilyInfrastructure); err != context.Canceled {
+		t.Fatalf("wait with a cancelled context: err = %v", err)
+	}
+	if d := l.reserve(FamilyInfrastructure); d != 2*time.Second {
+		t.Errorf("reserve after a cancelled wait: wait %v, want 2s", d)
+	}
+}
+
+func TestRateLimiterShared(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{Rate: 10, Burst: 1})
+
+	const n = 10
+	waits := make([]time.Duration, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			waits[i] = l.reserve(FamilyInfrastructure)
+		}(i)
+	}
+	wg.Wait()
+
+	// Every goroutine gets its own slot, 100ms after the previous one.
+	sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+	for i, d := range waits {
+		if want := time.Duration(i) * 100 * time.Millisecond; d < want-time.Millisecond || d > want+time.Millisecond {
+			t.Errorf("wait %d = %v, want %v", i, d, want)
+		}
+	}
+}
+
+func TestRateLimiterExhaustedWindow(t *testing.T) {
+	l, clock := newTestLimiter(RateLimit{Rate: 4, Burst: 8})
+	start := clock.now()
+
+	res := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	res.Header.Set("RateLimit-Limit", "100")
+	res.Header.Set("RateLimit-Remaining", "0")
+	res.Header.Set("RateLimit-Reset", "10")
+	l.observe(FamilyInfrastructure, res)
+
+	budget := l.budget(FamilyInfrastructure)
+	if budget.Limit != 100 || budget.Remaining != 0 || budget.Tokens != 0 {
+		t.Errorf("budget = %+v, want Limit 100, Remaining 0 and no tokens", budget)
+	}
+	if want := start.Add(10 * time.Second); !budget.Reset.Equal(want) || !budget.BlockedUntil.Equal(want) {
+		t.Errorf("budget Reset = %v, BlockedUntil = %v, want both %v", budget.Reset, budget.BlockedUntil, want)
+	}
+	if d := l.reserve(FamilyInfrastructure); d != 10*time.Second {
+		t.Errorf("reserve in an exhausted window: wait %v, want 10s", d)
+	}
+
+	clock.advance(10 * time.Second)
+	if d := l.reserve(FamilyInfrastructure); d != 0 {
+		t.Errorf("reserve after the reset: wait %v, want 0", d)
+	}
+	if budget := l.budget(FamilyInfrastructure); !budget.BlockedUntil.IsZero() {
+		t.Errorf("BlockedUntil after the reset = %v, want zero", budget.BlockedUntil)
+	}
+}
@@ -0,0 +1,318 @@
+package nsgo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// RetryPolicy controls which failed requests a Client retries and how long it waits between
+// attempts. Start from DefaultRetryPolicy and adjust it:
+//
+//	policy := nsgo.DefaultRetryPolicy()
+//	policy.MaxRetries = 3
+//	policy.VerifyCreates = true
+//	nsclient, err := nsgo.New(baseURL, nsgo.WithAPIToken(token), nsgo.WithRetryPolicy(policy))
+//
+// Only idempotent methods are retried by default. Creating a publisher, a private app, an IPSec
+// tunnel or an upgrade profile, and getting a registration token, are POST requests: retrying one
+// the tenant processed despite answering with an error would create a duplicate or mint another token.
+type RetryPolicy struct {
+	// MaxRetries is the number of attempts made after the first one.
+	MaxRetries int
+	// Methods are the HTTP methods retried. Nil means GET, HEAD, OPTIONS, PUT and DELETE.
+	Methods []string
+	// Statuses are the HTTP status codes retried. Nil means 429, 500, 502, 503 and 504.
+	Statuses []int
+	// RetryError reports whether a request that got no response because of err is retried.
+	// Nil retries network errors, but not invalid URLs, redirect loops or certificate errors.
+	// Requests whose context is done are never retried.
+	RetryError func(err error) bool
+	// MinWait and MaxWait bound the wait before a retry, which doubles from MinWait with every
+	// attempt. A Retry-After header sent with a 429 or a 503 lengthens it, up to MaxWait.
+	MinWait time.Duration
+	MaxWait time.Duration
+	// Jitter is the fraction of every wait, between 0 and 1, that is randomized so that clients
+	// failing together do not retry together.
+	Jitter float64
+	// Budget caps the retries of the client, all calls together, to Budget per minute so that
+	// retries do not multiply the load on a struggling tenant. Zero means no cap.
+	Budget int
+	// VerifyCreates retries the creates of publishers, private apps, IPSec tunnels and upgrade
+	// profiles that failed with a retried status or error, after looking the object up by name:
+	// when the tenant created it despite the failure, it is returned instead of created again.
+	// It has no effect when Methods lists POST, as creates are then retried like any request.
+	VerifyCreates bool
+}
+
+var (
+	defaultRetryMethods  = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete}
+	defaultRetryStatuses = []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+)
+
+// DefaultRetryPolicy returns the recommended policy: up to 4 retries of idempotent requests,
+// waiting from 500ms to 30s, with up to 100 retries per minute.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 4,
+		MinWait:    500 * time.Millisecond,
+		MaxWait:    30 * time.Second,
+		Jitter:     0.5,
+		Budget:     100,
+	}
+}
+
+// WithRetryPolicy enables automatic retries as set by p.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *clientOptions) error {
+		switch {
+		case p.MaxRetries < 0:
+			return errors.New("nsgo: negative retry count")
+		case p.MinWait < 0 || p.MaxWait < 0:
+			return errors.New("nsgo: negative retry wait")
+		case p.MaxWait < p.MinWait:
+			return errors.New("nsgo: retry MaxWait is less than MinWait")
+		case p.Jitter < 0 || p.Jitter > 1:
+			return errors.New("nsgo: retry jitter is not between 0 and 1")
+		case p.Budget < 0:
+			return errors.New("nsgo: negative retry budget")
+		}
+		p.Methods = append([]string(nil), p.Methods...)
+		p.Statuses = append([]int(nil), p.Statuses...)
+		o.retry = &p
+		return nil
+	}
+}
+
+// policy converts c to a RetryPolicy, with waits in whole seconds and the budget of DefaultRetryPolicy,
+// which keeps the 100 retries of NewRetryClient from adding up across calls.
+func (c RetryConfig) policy() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.MaxRetries = c.RetryMax
+	p.MinWait = time.Duration(c.RetryWaitMin) * time.Second
+	p.MaxWait = time.Duration(c.RetryWaitMax) * time.Second
+	if p.MaxWait < p.MinWait {
+		p.MaxWait = p.MinWait
+	}
+	return p
+}
+
+func (p *RetryPolicy) retriesMethod(method string) bool {
+	methods := p.Methods
+	if len(methods) == 0 {
+		methods = defaultRetryMethods
+	}
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) retriesStatus(status int) bool {
+	statuses := p.Statuses
+	if len(statuses) == 0 {
+		statuses = defaultRetryStatuses
+	}
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) retriesError(err error) bool {
+	if p.RetryError != nil {
+		return p.RetryError(err)
+	}
+	retry, _ := retryablehttp.DefaultRetryPolicy(context.Background(), nil, err)
+	return retry
+}
+
+// retries reports whether a call that failed with err, as returned by the services, is retried.
+func (p *RetryPolicy) retries(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode != http.StatusOK && p.retriesStatus(apiErr.StatusCode)
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && p.retriesError(urlErr)
+}
+
+// backoff returns the wait before retry number attempt, starting at 0, given the delay
+// the tenant asked for, if any.
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	wait := p.MinWait
+	for i := 0; i < attempt && wait < p.MaxWait; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	if p.Jitter > 0 {
+		wait -= time.Duration(p.Jitter * rand.Float64() * float64(wait))
+	}
+	if retryAfter > wait {
+		wait = min(retryAfter, p.MaxWait)
+	}
+	return wait
+}
+
+// retryBudget is a token bucket refilled with max retries per minute.
+type retryBudget struct {
+	mu     sync.Mutex
+	max    float64
+	tokens float64
+	last   time.Time
+}
+
+// newRetryBudget returns a budget of n retries per minute, or nil, allowing any retry, when n is zero.
+func newRetryBudget(n int) *retryBudget {
+	if n <= 0 {
+		return nil
+	}
+	return &retryBudget{max: float64(n), tokens: float64(n), last: time.Now()}
+}
+
+// take reports whether a retry is allowed, and counts it if so.
+func (b *retryBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.max, b.tokens+now.Sub(b.last).Minutes()*b.max)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type methodKey struct{}
+
+// retryTransport retries the requests sent through rt as set by a RetryPolicy.
+type retryTransport struct {
+	rt *retryablehttp.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	//retryablehttp only hands the context to the retry check, which needs the method.
+	return t.rt.RoundTrip(req.WithContext(context.WithValue(req.Context(), methodKey{}, req.Method)))
+}
+
+// newRetryTransport wraps transport in a retryablehttp round tripper retrying as set by p,
+// within budget. retryLogger receives the logs of retryablehttp, logger those of the policy.
+func newRetryTransport(transport http.RoundTripper, p *RetryPolicy, budget *retryBudget, logger *slog.Logger, retryLogger interface{}) http.RoundTripper {
+	retryClient := retryablehttp.NewClient()
+	retryClient.HTTPClient = &http.Client{Transport: transport}
+	retryClient.RetryMax = p.MaxRetries
+	retryClient.RetryWaitMin = p.MinWait
+	retryClient.RetryWaitMax = p.MaxWait
+	retryClient.Logger = retryLogger
+	//Hand the last response back once retries are exhausted so it surfaces as an APIError.
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+
+	retryClient.CheckRetry = func(ctx context.Context, res *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		method, _ := ctx.Value(methodKey{}).(string)
+		if !p.retriesMethod(method) {
+			return false, nil
+		}
+		switch {
+		case err != nil:
+			if !p.retriesError(err) {
+				return false, err
+			}
+		case !p.retriesStatus(res.StatusCode):
+			return false, nil
+		}
+		if !budget.take() {
+			logger.WarnContext(ctx, "nsgo: retry budget exhausted, not retrying", "method", method)
+			return false, nil
+		}
+		return true, nil
+	}
+	retryClient.Backoff = func(_, _ time.Duration, attempt int, res *http.Response) time.Duration {
+		var retryAfter time.Duration
+		if res != nil && (res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable) {
+			retryAfter = parseDelay(res.Header.Get("Retry-After"))
+		}
+		return p.backoff(attempt, retryAfter)
+	}
+
+	return &retryTransport{rt: &retryablehttp.RoundTripper{Client: retryClient}}
+}
+
+// verifiedCreate sends a create with send. When it fails with an error the retry policy of c
+// retries and the policy verifies creates, lookup is called before every retry: the object it
+// finds, if any, was created by the failed attempt and is returned. lookup returns nil when
+// there is no such object.
+func verifiedCreate[T any](ctx context.Context, c *Client, send, lookup func(context.Context) (*T, error)) (*T, error) {
+	obj, err := send(ctx)
+	p := c.retry
+	if err == nil || p == nil || !p.VerifyCreates || p.retriesMethod(http.MethodPost) {
+		return obj, err
+	}
+	for attempt := 0; attempt < p.MaxRetries && p.retries(ctx, err); attempt++ {
+		if !c.retryBudget.take() {
+			c.logger.WarnContext(ctx, "nsgo: retry budget exhausted, not retrying", "method", http.MethodPost)
+			break
+		}
+		var retryAfter time.Duration
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			retryAfter = apiErr.RateLimit.RetryAfter
+		}
+		if err := sleep(ctx, p.backoff(attempt, retryAfter)); err != nil {
+			return nil, err
+		}
+
+		found, lookupErr := lookup(ctx)
+		if lookupErr != nil {
+			//Without the lookup, retrying could create a duplicate.
+			c.logger.WarnContext(ctx, "nsgo: cannot verify failed create, not retrying", "error", lookupErr)
+			break
+		}
+		if found != nil {
+			c.logger.InfoContext(ctx, "nsgo: failed create did succeed", "error", err)
+			return found, nil
+		}
+		if obj, err = send(ctx); err == nil {
+			return obj, nil
+		}
+	}
+	return nil, err
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
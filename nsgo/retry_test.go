@@ -0,0 +1,150 @@
+package nsgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func fastRetryPolicy() nsgo.RetryPolicy {
+	policy := nsgo.DefaultRetryPolicy()
+	policy.MinWait = time.Millisecond
+	policy.MaxWait = 10 * time.Millisecond
+	return policy
+}
+
+func TestRetryPolicyMethods(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client(nsgo.WithRetryPolicy(fastRetryPolicy()))
+	ctx := context.Background()
+
+	srv.Fail(nsgotest.Failure{Path: "/api/v2/infrastructure/publishers", Status: http.StatusServiceUnavailable, Times: 2})
+	if _, err := nsclient.Publishers.List(ctx); err != nil {
+		t.Fatalf("Publishers.List: %v", err)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("GET: server received %d requests, want 3", n)
+	}
+
+	srv.Fail(nsgotest.Failure{Method: http.MethodPost, Path: "/api/v2/infrastructure/publishers", Status: http.StatusServiceUnavailable})
+	_, err := nsclient.Publishers.Create(ctx, nsgo.PublisherOptions{Name: "pub-1"})
+	if !errors.Is(err, nsgo.ErrServer) {
+		t.Errorf("Publishers.Create: err = %v, want ErrServer", err)
+	}
+	if n := len(srv.Requests()); n != 4 {
+		t.Errorf("POST: server received %d requests in all, want 4", n)
+	}
+}
+
+func TestRetryPolicyStatuses(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	policy := fastRetryPolicy()
+	policy.Statuses = []int{http.StatusBadGateway}
+	nsclient := srv.Client(nsgo.WithRetryPolicy(policy))
+
+	srv.Fail(nsgotest.Failure{Status: http.StatusServiceUnavailable})
+	if _, err := nsclient.PrivateApps.List(context.Background()); err == nil {
+		t.Fatal("PrivateApps.List succeeded, want the 503")
+	}
+	srv.Fail(nsgotest.Failure{Status: http.StatusBadGateway})
+	if _, err := nsclient.PrivateApps.List(context.Background()); err != nil {
+		t.Fatalf("PrivateApps.List: %v", err)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("server received %d requests, want 3", n)
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	policy := nsgo.RetryPolicy{MaxRetries: 1, MinWait: 50 * time.Millisecond, MaxWait: 50 * time.Millisecond}
+	nsclient := srv.Client(nsgo.WithRetryPolicy(policy))
+
+	srv.Fail(nsgotest.Failure{Status: http.StatusBadGateway})
+	start := time.Now()
+	if _, err := nsclient.IPsec.ListPops(context.Background()); err != nil {
+		t.Fatalf("IPsec.ListPops: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("retried after %v, want 50ms", elapsed)
+	}
+
+	for _, p := range []nsgo.RetryPolicy{{MaxRetries: -1}, {MinWait: time.Second}, {Jitter: 2}} {
+		if _, err := nsgo.New(srv.URL, nsgo.WithRetryPolicy(p)); err == nil {
+			t.Errorf("New accepted the retry policy %+v", p)
+		}
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	policy := fastRetryPolicy()
+	policy.Budget = 1
+	nsclient := srv.Client(nsgo.WithRetryPolicy(policy))
+
+	srv.Fail(nsgotest.Failure{Status: http.StatusServiceUnavailable, Times: 10})
+	for i := 0; i < 2; i++ {
+		if _, err := nsclient.Publishers.List(context.Background()); err == nil {
+			t.Fatal("Publishers.List succeeded, want the 503")
+		}
+	}
+	// The first call spends the budget on a single retry; the second one gets none.
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("server received %d requests, want 3", n)
+	}
+}
+
+func TestVerifyCreates(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	policy := fastRetryPolicy()
+	policy.VerifyCreates = true
+	nsclient := srv.Client(nsgo.WithRetryPolicy(policy))
+	ctx := context.Background()
+
+	// The tenant created the publisher but the gateway answered with an error.
+	srv.Fail(nsgotest.Failure{Method: http.MethodPost, Path: "/api/v2/infrastructure/publishers", Status: http.StatusGatewayTimeout, AfterServing: true})
+	pub, err := nsclient.Publishers.Create(ctx, nsgo.PublisherOptions{Name: "pub-1"})
+	if err != nil {
+		t.Fatalf("Publishers.Create: %v", err)
+	}
+	if pub.Name != "pub-1" {
+		t.Errorf("Publishers.Create returned %q, want pub-1", pub.Name)
+	}
+
+	// The tenant did not create the app, so the create is sent again.
+	srv.Fail(nsgotest.Failure{Method: http.MethodPost, Path: "/api/v2/steering/apps/private", Status: http.StatusBadGateway})
+	app, err := nsclient.PrivateApps.Create(ctx, nsgo.PrivateApp{AppName: "app-1", Host: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("PrivateApps.Create: %v", err)
+	}
+	if app.AppName != "app-1" {
+		t.Errorf("PrivateApps.Create returned %q, want app-1", app.AppName)
+	}
+
+	posts := 0
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodPost {
+			posts++
+		}
+	}
+	if posts != 3 {
+		t.Errorf("server received %d creates, want 3", posts)
+	}
+	list, err := nsclient.Publishers.List(ctx)
+	if err != nil {
+		t.Fatalf("Publishers.List: %v", err)
+	}
+	if len(list.Publishers) != 1 {
+		t.Errorf("server has %d publishers, want 1", len(list.Publishers))
+	}
+}
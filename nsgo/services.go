@@ -0,0 +1,103 @@
+package nsgo
+
+import "context"
+
+// PublishersService is the set of operations on publishers. It grows as operations on publishers
+// are added to the client; see the note above BulkUpgrade on implementing it outside this package.
+type PublishersService interface {
+	// List returns every publisher of the tenant.
+	List(ctx context.Context) (*PublishersList, error)
+	// ListWithFilter returns the publishers matching filter (i.e. `publisher_name eq "pub-1"`).
+	ListWithFilter(ctx context.Context, filter string) (*PublishersList, error)
+	// Pages returns a Pager over the publishers of the tenant.
+	Pages(opts ListOptions) *Pager[PublisherSummary]
+	// Get returns the publisher identified by options.Id.
+	Get(ctx context.Context, options PublisherOptions) (*Publisher, error)
+	// Create creates a publisher named options.Name.
+	Create(ctx context.Context, options PublisherOptions) (*Publisher, error)
+	// Update changes the fields set in options of the publisher identified by options.Id.
+	Update(ctx context.Context, options PublisherOptions) (*Publisher, error)
+	// Replace replaces the publisher identified by options.Id.
+	Replace(ctx context.Context, options PublisherOptions) (*Publisher, error)
+	// Delete deletes the publisher identified by options.Id.
+	Delete(ctx context.Context, options PublisherOptions) error
+	// Token mints a registration token for the publisher identified by options.Id.
+	Token(ctx context.Context, options PublisherOptions) (*PublisherToken, error)
+
+	// The methods below were added to the interface after the ones above, and broke the types
+	// implementing it outside this package. Fakes should embed PublishersService, or the fake of
+	// the nsgomock package, and override the methods they need, so that they keep compiling when
+	// more are added.
+
+	// BulkUpgrade requests the upgrade of the publishers with the given IDs, and returns the
+	// outcome for each of them in the same order. PublishersList.UpgradeResults reports the
+	// progress of the upgrades.
+	BulkUpgrade(ctx context.Context, ids []int) ([]PublisherUpgradeOutcome, error)
+	// Apps returns the private apps served by the publisher with the given ID.
+	Apps(ctx context.Context, publisherID int) ([]PublisherApp, error)
+	// Releases returns the publisher releases the tenant can upgrade publishers to.
+	Releases(ctx context.Context) ([]PublisherRelease, error)
+	// AlertsConfiguration returns which publisher events are emailed to admins.
+	AlertsConfiguration(ctx context.Context) (*PublisherAlertsConfiguration, error)
+	// UpdateAlertsConfiguration replaces the publisher alerts configuration with config.
+	UpdateAlertsConfiguration(ctx context.Context, config PublisherAlertsConfiguration) (*PublisherAlertsConfiguration, error)
+}
+
+// PrivateAppsService is the set of operations on private apps. Like PublishersService, it may grow.
+type PrivateAppsService interface {
+	// List returns every private app of the tenant.
+	List(ctx context.Context) (*PrivateAppsList, error)
+	// ListWithFilter returns the private apps matching filter (i.e. `app_name eq "wiki"`).
+	ListWithFilter(ctx context.Context, filter string) (*PrivateAppsList, error)
+	// Pages returns a Pager over the private apps of the tenant.
+	Pages(opts ListOptions) *Pager[PrivateAppSummary]
+	// Get returns the private app identified by options.Id.
+	Get(ctx context.Context, options PrivateAppOptions) (*PrivateApp, error)
+	// Create creates app.
+	Create(ctx context.Context, app PrivateApp) (*PrivateApp, error)
+	// Update changes the fields set in app of the private app identified by options.Id.
+	Update(ctx context.Context, options PrivateAppOptions, app PrivateApp) (*PrivateApp, error)
+	// Replace replaces the private app identified by options.Id with app.
+	Replace(ctx context.Context, options PrivateAppOptions, app PrivateApp) (*PrivateApp, error)
+	// Delete deletes the private app identified by options.Id.
+	Delete(ctx context.Context, options PrivateAppOptions) error
+}
+
+// UpgradeProfilesService is the set of operations on publisher upgrade profiles. Like
+// PublishersService, it may grow.
+type UpgradeProfilesService interface {
+	// List returns every publisher upgrade profile of the tenant.
+	List(ctx context.Context) (*PublisherUpgradeProfiles, error)
+	// Pages returns a Pager over the publisher upgrade profiles of the tenant.
+	Pages(opts ListOptions) *Pager[PublisherUpgradeProfile]
+	// Get returns the profile identified by options.ExternalID, if set, or options.ID.
+	Get(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error)
+	// Create creates a profile from options. See ValidateDockerTag to check options.DockerTag first.
+	Create(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error)
+	// Update updates the profile identified by options.ID, if set, or options.ExternalID.
+	Update(ctx context.Context, options PublisherUpgradeProfileOptions) (*PublisherUpgradeProfile, error)
+	// Delete deletes the profile identified by options.ExternalID, if set, or options.ID.
+	Delete(ctx context.Context, options PublisherUpgradeProfileOptions) error
+}
+
+// IPsecService is the set of operations on IPSec PoPs and tunnels. Like PublishersService, it may grow.
+type IPsecService interface {
+	// ListPops returns every IPSec PoP.
+	ListPops(ctx context.Context) (*IpsecPops, error)
+	// ListPopsWithFilters returns the IPSec PoPs matching filters.
+	ListPopsWithFilters(ctx context.Context, filters PopFilters) (*IpsecPops, error)
+	// GetPop returns the IPSec PoP identified by options.Id.
+	GetPop(ctx context.Context, options RequestOptions) (*IpsecPop, error)
+	// ListTunnels returns every IPSec tunnel of the tenant.
+	ListTunnels(ctx context.Context) (*IpsecTunnels, error)
+	// TunnelPages returns a Pager over the IPSec tunnels of the tenant.
+	TunnelPages(opts ListOptions) *Pager[IpsecTunnel]
+	// GetTunnel returns the IPSec tunnel identified by options.Id.
+	GetTunnel(ctx context.Context, options RequestOptions) (*IpsecTunnel, error)
+	// CreateTunnel creates tunnel.
+	CreateTunnel(ctx context.Context, tunnel NewIpsecTunnel) (*IpsecTunnel, error)
+	// UpdateTunnel changes the fields set in tunnel of the IPSec tunnel identified by options.Id.
+	UpdateTunnel(ctx context.Context, options RequestOptions, tunnel NewIpsecTunnel) (*IpsecTunnel, error)
+	// DeleteTunnel deletes the IPSec tunnel identified by options.Id.
+	DeleteTunnel(ctx context.Context, options RequestOptions) (*IpsecTunnel, error)
+}
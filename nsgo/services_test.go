@@ -0,0 +1,76 @@
+package nsgo_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/netskopeoss/netskope-api-client-go/nsgo"
+	"github.com/netskopeoss/netskope-api-client-go/nsgo/nsgotest"
+)
+
+func TestServices(t *testing.T) {
+	srv := nsgotest.NewServer()
+	defer srv.Close()
+	nsclient := srv.Client()
+	ctx := context.Background()
+
+	pub, err := nsclient.Publishers.Create(ctx, nsgo.PublisherOptions{Name: "pub-1"})
+	if err != nil {
+		t.Fatalf("Publishers.Create: %v", err)
+	}
+	if _, err := nsclient.PrivateApps.Create(ctx, nsgo.PrivateApp{AppName: "wiki", Host: "wiki.internal"}); err != nil {
+		t.Fatalf("PrivateApps.Create: %v", err)
+	}
+	if _, err := nsclient.UpgradeProfiles.Create(ctx, nsgo.PublisherUpgradeProfileOptions{Name: "weekly"}); err != nil {
+		t.Fatalf("UpgradeProfiles.Create: %v", err)
+	}
+	if _, err := nsclient.IPsec.CreateTunnel(ctx, nsgo.NewIpsecTunnel{Site: "branch-1"}); err != nil {
+		t.Fatalf("IPsec.CreateTunnel: %v", err)
+	}
+
+	if err := nsclient.Publishers.Delete(ctx, nsgo.PublisherOptions{Id: strconv.Itoa(pub.ID)}); err != nil {
+		t.Errorf("Publishers.Delete: %v", err)
+	}
+	if pubs, err := nsclient.Publishers.List(ctx); err != nil || len(pubs.Publishers) != 0 {
+		t.Errorf("Publishers.List after delete = %+v, %v", pubs, err)
+	}
+	if apps, err := nsclient.PrivateApps.Pages(nsgo.ListOptions{}).All(ctx); err != nil || len(apps) != 1 {
+		t.Errorf("PrivateApps.Pages.All = %+v, %v", apps, err)
+	}
+	if profiles, err := nsclient.UpgradeProfiles.List(ctx); err != nil || len(profiles.UpgradeProfiles) != 1 {
+		t.Errorf("UpgradeProfiles.List = %+v, %v", profiles, err)
+	}
+	if tunnels, err := nsclient.IPsec.TunnelPages(nsgo.ListOptions{}).All(ctx); err != nil || len(tunnels) != 1 {
+		t.Errorf("IPsec.TunnelPages.All = %+v, %v", tunnels, err)
+	}
+}
+
+// connectedPublisher is a fake embedding PublishersService, serving a single connected publisher.
+type connectedPublisher struct {
+	nsgo.PublishersService
+}
+
+func (connectedPublisher) ListWithFilter(ctx context.Context, filter string) (*nsgo.PublishersList, error) {
+	return &nsgo.PublishersList{Publishers: []nsgo.PublisherSummary{{PublisherID: 7, PublisherName: "pub-1"}}}, nil
+}
+
+func (connectedPublisher) Get(ctx context.Context, options nsgo.PublisherOptions) (*nsgo.Publisher, error) {
+	return &nsgo.Publisher{ID: 7, Name: "pub-1", Registered: true, Status: nsgo.PublisherStatusConnected}, nil
+}
+
+func TestServicesEmbedded(t *testing.T) {
+	nsclient, _ := nsgo.New("https://example-tenant.goskope.com")
+	nsclient.Publishers = connectedPublisher{nsclient.Publishers}
+
+	res, err := nsclient.ProvisionPublisher(context.Background(), nsgo.ProvisionOptions{
+		Publisher: nsgo.PublisherOptions{Name: "pub-1"},
+		Deploy: func(ctx context.Context, p *nsgo.Publisher, token string) error {
+			t.Error("Deploy called for a registered publisher")
+			return nil
+		},
+	})
+	if err != nil || res.Publisher.ID != 7 {
+		t.Errorf("ProvisionPublisher = %+v, %v", res, err)
+	}
+}